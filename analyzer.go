@@ -0,0 +1,248 @@
+package sqlx
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Fingerprint returns a normalized form of query, so that queries which only
+// differ in their literal values or bindvars share a fingerprint.  String,
+// numeric and dollar-quoted literals and bindvars of any bindtype are replaced
+// with `?`, lists of them such as those expanded by In collapse to `?+`,
+// comments are removed and whitespace is collapsed.
+func Fingerprint(query string) string {
+	var b strings.Builder
+	b.Grow(len(query))
+	space := false
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		if end := literalEnd(query, i, false); end > i {
+			switch c {
+			case '"', '`':
+				// quoted identifiers are not values
+				writeFingerprint(&b, &space, query[i:end])
+			case '-', '/':
+				// comments are dropped, but still separate tokens
+				space = true
+			default:
+				writeValue(&b, &space)
+			}
+			i = end - 1
+			continue
+		}
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			space = true
+		case c == '?':
+			writeValue(&b, &space)
+		case c == '$' || c == '@' || c == ':':
+			// $1, @p1 and :name bindvars;  other uses of these are copied
+			j := i + 1
+			if c == '@' && j < len(query) && query[j] == 'p' {
+				j++
+			}
+			start := j
+			for j < len(query) && (isIdentByte(query[j]) && query[j] != '$') {
+				j++
+			}
+			name := query[start:j]
+			prev := byte(0)
+			if i > 0 {
+				prev = query[i-1]
+			}
+			// `::` casts and identifiers containing `$` are not bindvars
+			bindvar := name != "" && prev != ':' && !isIdentByte(prev)
+			if c != ':' {
+				bindvar = bindvar && isDigits(name)
+			}
+			if bindvar {
+				writeValue(&b, &space)
+				i = j - 1
+				continue
+			}
+			writeFingerprint(&b, &space, query[i:i+1])
+		case '0' <= c && c <= '9' && (i == 0 || !isIdentByte(query[i-1])):
+			j := i
+			for j < len(query) && (('0' <= query[j] && query[j] <= '9') || query[j] == '.') {
+				j++
+			}
+			writeValue(&b, &space)
+			i = j - 1
+		default:
+			writeFingerprint(&b, &space, query[i:i+1])
+		}
+	}
+	return collapseLists(b.String())
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return len(s) > 0
+}
+
+func writeFingerprint(b *strings.Builder, space *bool, s string) {
+	if *space && b.Len() > 0 {
+		b.WriteByte(' ')
+	}
+	*space = false
+	b.WriteString(s)
+}
+
+func writeValue(b *strings.Builder, space *bool) {
+	writeFingerprint(b, space, "?")
+}
+
+// collapseLists replaces lists of values, eg. `(?, ?, ?)`, with `(?+)`.
+func collapseLists(s string) string {
+	if !strings.Contains(s, "?,") {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] != '?' {
+			b.WriteByte(s[i])
+			continue
+		}
+		j := i + 1
+		n := 1
+		for {
+			k := j
+			if k < len(s) && s[k] == ',' {
+				k++
+			}
+			if k < len(s) && s[k] == ' ' {
+				k++
+			}
+			if k == j || k >= len(s) || s[k] != '?' || (k+1 < len(s) && isIdentByte(s[k+1])) {
+				break
+			}
+			j = k + 1
+			n++
+		}
+		if n > 1 && i > 0 && s[i-1] == '(' && j < len(s) && s[j] == ')' {
+			b.WriteString("?+")
+			i = j - 1
+		} else {
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// SlowQuery is reported by an Analyzer for a query which took at least its
+// SlowThreshold.
+type SlowQuery struct {
+	Fingerprint string
+	Query       string
+	Args        []any
+	Duration    time.Duration
+}
+
+// RepeatedQuery is reported by an Analyzer when the same fingerprint is run
+// RepeatThreshold times within one tracked context, which usually means that
+// a query is run in a loop instead of once for all of the rows;  an N+1.
+type RepeatedQuery struct {
+	Fingerprint string
+	Query       string
+	Count       int
+}
+
+// An Analyzer is a Hook which detects slow and repeated queries.  Add it to a
+// DB with AddHook, and use Track to start counting queries per context, eg.
+// once per incoming request:
+//
+//	a := &sqlx.Analyzer{
+//		SlowThreshold:   100 * time.Millisecond,
+//		RepeatThreshold: 10,
+//		OnSlowQuery:     func(ctx context.Context, q sqlx.SlowQuery) { ... },
+//		OnRepeatedQuery: func(ctx context.Context, q sqlx.RepeatedQuery) { ... },
+//	}
+//	db.AddHook(a)
+//	...
+//	ctx = a.Track(r.Context())
+//
+// Slow queries are reported whether or not their context is tracked.
+type Analyzer struct {
+	// SlowThreshold is the duration after which a query is slow.  If it is
+	// zero, slow queries are not reported.
+	SlowThreshold time.Duration
+	// RepeatThreshold is how many times a fingerprint has to be run within a
+	// tracked context before it is reported.  If it is zero, repeated queries
+	// are not reported.
+	RepeatThreshold int
+	// OnSlowQuery is called for each slow query.
+	OnSlowQuery func(context.Context, SlowQuery)
+	// OnRepeatedQuery is called once per tracked context and fingerprint, when
+	// it reaches the RepeatThreshold.
+	OnRepeatedQuery func(context.Context, RepeatedQuery)
+}
+
+var _ Hook = (*Analyzer)(nil)
+
+type analyzerKey struct{ a *Analyzer }
+
+// analyzerScope counts the fingerprints run within a tracked context.
+type analyzerScope struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+// Track returns a context in which the Analyzer counts queries by fingerprint.
+// Contexts derived from it share the same counts.
+func (a *Analyzer) Track(ctx context.Context) context.Context {
+	return context.WithValue(ctx, analyzerKey{a}, &analyzerScope{counts: map[string]int{}})
+}
+
+// Counts returns the number of times each fingerprint was run within ctx, or
+// nil if ctx is not tracked.
+func (a *Analyzer) Counts(ctx context.Context) map[string]int {
+	scope, ok := ctx.Value(analyzerKey{a}).(*analyzerScope)
+	if !ok {
+		return nil
+	}
+	scope.mu.Lock()
+	defer scope.mu.Unlock()
+	counts := make(map[string]int, len(scope.counts))
+	for fp, n := range scope.counts {
+		counts[fp] = n
+	}
+	return counts
+}
+
+// BeforeQuery implements Hook.
+func (a *Analyzer) BeforeQuery(ctx context.Context, e *QueryEvent) context.Context {
+	return ctx
+}
+
+// AfterQuery implements Hook.
+func (a *Analyzer) AfterQuery(ctx context.Context, e *QueryEvent) {
+	scope, tracked := ctx.Value(analyzerKey{a}).(*analyzerScope)
+	slow := a.SlowThreshold > 0 && e.Duration >= a.SlowThreshold
+	if !tracked && !slow {
+		return
+	}
+
+	fp := Fingerprint(e.Query)
+	if slow && a.OnSlowQuery != nil {
+		a.OnSlowQuery(ctx, SlowQuery{Fingerprint: fp, Query: e.Query, Args: e.Args, Duration: e.Duration})
+	}
+	if !tracked {
+		return
+	}
+
+	scope.mu.Lock()
+	scope.counts[fp]++
+	n := scope.counts[fp]
+	scope.mu.Unlock()
+	if n == a.RepeatThreshold && a.OnRepeatedQuery != nil {
+		a.OnRepeatedQuery(ctx, RepeatedQuery{Fingerprint: fp, Query: e.Query, Count: n})
+	}
+}
@@ -0,0 +1,72 @@
+package sqlx
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFingerprint(t *testing.T) {
+	var tests = []struct {
+		query, fingerprint string
+	}{
+		{"SELECT * FROM person WHERE id = 1", "SELECT * FROM person WHERE id = ?"},
+		{"SELECT * FROM person WHERE id = ?", "SELECT * FROM person WHERE id = ?"},
+		{"SELECT * FROM person WHERE id = $1", "SELECT * FROM person WHERE id = ?"},
+		{"SELECT * FROM person WHERE id = @p1", "SELECT * FROM person WHERE id = ?"},
+		{"SELECT * FROM person WHERE name = :name", "SELECT * FROM person WHERE name = ?"},
+		{"SELECT * FROM person\n\tWHERE  name = 'it''s' -- comment\n", "SELECT * FROM person WHERE name = ?"},
+		{"SELECT /* hint */ 1.5, $$body$$", "SELECT ?, ?"},
+		{"SELECT * FROM t1 WHERE id IN (1, 2, 3)", "SELECT * FROM t1 WHERE id IN (?+)"},
+		{"SELECT * FROM t1 WHERE id IN ($1,$2)", "SELECT * FROM t1 WHERE id IN (?+)"},
+		{`SELECT "col1", id::text FROM t1`, `SELECT "col1", id::text FROM t1`},
+		{"SELECT a$1 FROM t2 WHERE @user = 1", "SELECT a$1 FROM t2 WHERE @user = ?"},
+	}
+	for _, test := range tests {
+		if fp := Fingerprint(test.query); fp != test.fingerprint {
+			t.Errorf("Fingerprint(%q): expected %q, got %q", test.query, test.fingerprint, fp)
+		}
+	}
+}
+
+func TestAnalyzer(t *testing.T) {
+	RunWithSchema(defaultSchema, t, func(db *DB, t *testing.T, now string) {
+		loadDefaultFixture(db, t)
+		var repeated []RepeatedQuery
+		var slow []SlowQuery
+		a := &Analyzer{
+			SlowThreshold:   1,
+			RepeatThreshold: 3,
+			OnSlowQuery:     func(ctx context.Context, q SlowQuery) { slow = append(slow, q) },
+			OnRepeatedQuery: func(ctx context.Context, q RepeatedQuery) { repeated = append(repeated, q) },
+		}
+		db.AddHook(a)
+
+		ctx := a.Track(context.Background())
+		query := db.Rebind("SELECT count(*) FROM person WHERE first_name = ?")
+		for _, name := range []string{"Jason", "John", "Jason", "Jane"} {
+			var n int
+			if err := db.GetContext(ctx, &n, query, name); err != nil {
+				t.Fatal(err)
+			}
+		}
+		fp := Fingerprint(query)
+		if len(repeated) != 1 || repeated[0].Count != 3 || repeated[0].Fingerprint != fp {
+			t.Errorf("expected the query to be reported once, got %#v", repeated)
+		}
+		if n := a.Counts(ctx)[fp]; n != 4 {
+			t.Errorf("expected 4 queries to be counted, got %d", n)
+		}
+		if len(slow) != 4 {
+			t.Errorf("expected every query to be slow, got %d", len(slow))
+		}
+
+		// untracked contexts are not counted
+		var n int
+		if err := db.Get(&n, query, "Jason"); err != nil {
+			t.Fatal(err)
+		}
+		if a.Counts(context.Background()) != nil || a.Counts(ctx)[fp] != 4 {
+			t.Error("expected the untracked query not to be counted")
+		}
+	})
+}
@@ -0,0 +1,167 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Maximum number of bind parameters a single statement may use, per bindtype.
+// These are the defaults for drivers which have no explicit limit set with
+// BindParamLimit.
+var defaultParamLimits = map[int]int{
+	UNKNOWN:  999,
+	QUESTION: 65535,
+	DOLLAR:   65535,
+	NAMED:    65535,
+	AT:       2100 - 1,
+}
+
+// Drivers whose limit differs from the default for their bindtype.
+var defaultDriverParamLimits = map[string]int{
+	// SQLITE_MAX_VARIABLE_NUMBER is 999 before sqlite 3.32.0 and 32766 since;
+	// mattn/go-sqlite3 bundles a newer amalgamation than that.
+	"sqlite3":   32766,
+	"nrsqlite3": 32766,
+}
+
+var paramLimits sync.Map
+
+func init() {
+	for driver, limit := range defaultDriverParamLimits {
+		BindParamLimit(driver, limit)
+	}
+}
+
+// ParamLimit returns the maximum number of bind parameters a single statement
+// may use for a given database given a drivername.
+func ParamLimit(driverName string) int {
+	if limit, ok := paramLimits.Load(driverName); ok {
+		return limit.(int)
+	}
+	return defaultParamLimits[BindType(driverName)]
+}
+
+// BindParamLimit sets the maximum number of bind parameters for driverName,
+// as used by NamedExecBatch to split its argument into chunks.
+func BindParamLimit(driverName string, limit int) {
+	paramLimits.Store(driverName, limit)
+}
+
+// NamedExecBatchContext binds a named INSERT query with every element of arg,
+// which must be an array or slice of structs or maps, and executes it.
+// Unlike NamedExec, which expands the VALUES clause for the whole slice into
+// a single statement, the slice is split into chunks which fit within the
+// ParamLimit of the driver, and one statement is executed per chunk.  The
+// total number of rows affected by all statements is returned.
+//
+// NamedExecBatchContext does not start a transaction itself;  the DB and Tx
+// methods of the same name ensure all chunks are run in one transaction.
+func NamedExecBatchContext(ctx context.Context, e ExtContext, query string, arg any) (int64, error) {
+	return namedExecBatch(e, query, arg, func(q string, args ...any) (sql.Result, error) {
+		return e.ExecContext(ctx, q, args...)
+	})
+}
+
+// NamedExecBatch is like NamedExecBatchContext, but uses the Ext's Exec.
+func NamedExecBatch(e Ext, query string, arg any) (int64, error) {
+	return namedExecBatch(e, query, arg, e.Exec)
+}
+
+func namedExecBatch(e binder, query string, arg any, exec func(string, ...any) (sql.Result, error)) (int64, error) {
+	v := reflect.Indirect(reflect.ValueOf(arg))
+	if k := v.Kind(); k != reflect.Slice && k != reflect.Array {
+		return 0, fmt.Errorf("sqlx.NamedExecBatch: expected a slice or array, got %T", arg)
+	}
+	length := v.Len()
+	if length == 0 {
+		return 0, fmt.Errorf("length of array is 0: %#v", arg)
+	}
+
+	_, names, err := compileNamed([]byte(query), QUESTION, backslashEscapes(e.DriverName()))
+	if err != nil {
+		return 0, err
+	}
+	chunkSize := length
+	if len(names) > 0 {
+		chunkSize = ParamLimit(e.DriverName()) / len(names)
+		if chunkSize == 0 {
+			return 0, errors.New("sqlx.NamedExecBatch: query has more bindvars than the driver allows")
+		}
+	}
+
+	bindType, backslashes := BindType(e.DriverName()), backslashEscapes(e.DriverName())
+	m := mapperFor(e)
+
+	var total int64
+	for start := 0; start < length; start += chunkSize {
+		end := start + chunkSize
+		if end > length {
+			end = length
+		}
+		q, args, err := bindArray(bindType, backslashes, query, sliceChunk(v, start, end), m)
+		if err != nil {
+			return total, err
+		}
+		res, err := exec(q, args...)
+		if err != nil {
+			return total, err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return total, err
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// sliceChunk returns the elements of v between start and end as something
+// bindArray can index.  Arrays which are not addressable cannot be resliced,
+// so their elements are copied.
+func sliceChunk(v reflect.Value, start, end int) any {
+	if v.Kind() == reflect.Slice || v.CanAddr() {
+		return v.Slice(start, end).Interface()
+	}
+	chunk := make([]any, 0, end-start)
+	for i := start; i < end; i++ {
+		chunk = append(chunk, v.Index(i).Interface())
+	}
+	return chunk
+}
+
+// NamedExecBatch using this DB.  All chunks are executed within a single
+// transaction, which is rolled back if any of them fails.
+// Any named placeholder parameters are replaced with fields from arg.
+func (db *DB) NamedExecBatch(query string, arg any) (int64, error) {
+	return db.NamedExecBatchContext(context.Background(), query, arg)
+}
+
+// NamedExecBatchContext using this DB.  All chunks are executed within a
+// single transaction, which is rolled back if any of them fails.
+// Any named placeholder parameters are replaced with fields from arg.
+func (db *DB) NamedExecBatchContext(ctx context.Context, query string, arg any) (n int64, err error) {
+	err = db.WithTxx(ctx, nil, func(tx *Tx) error {
+		n, err = NamedExecBatchContext(ctx, tx, query, arg)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// NamedExecBatch within a transaction.
+// Any named placeholder parameters are replaced with fields from arg.
+func (tx *Tx) NamedExecBatch(query string, arg any) (int64, error) {
+	return NamedExecBatch(tx, query, arg)
+}
+
+// NamedExecBatchContext within a transaction.
+// Any named placeholder parameters are replaced with fields from arg.
+func (tx *Tx) NamedExecBatchContext(ctx context.Context, query string, arg any) (int64, error) {
+	return NamedExecBatchContext(ctx, tx, query, arg)
+}
@@ -0,0 +1,63 @@
+package sqlx
+
+import (
+	"testing"
+)
+
+func TestNamedExecBatch(t *testing.T) {
+	RunWithSchema(defaultSchema, t, func(db *DB, t *testing.T, now string) {
+		// three bindvars per row, so this allows two rows per statement
+		limit := ParamLimit(db.DriverName())
+		BindParamLimit(db.DriverName(), 7)
+		defer BindParamLimit(db.DriverName(), limit)
+
+		people := make([]Person, 0, 9)
+		for i := 0; i < cap(people); i++ {
+			people = append(people, Person{FirstName: "batch", LastName: string(rune('a' + i)), Email: "b@example.com"})
+		}
+
+		q := `INSERT INTO person (first_name, last_name, email) VALUES (:first_name, :last_name, :email)`
+		n, err := db.NamedExecBatch(q, people)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != int64(len(people)) {
+			t.Errorf("expected %d rows affected, got %d", len(people), n)
+		}
+
+		var count int
+		db.Get(&count, "SELECT count(*) FROM person WHERE first_name = 'batch'")
+		if count != len(people) {
+			t.Errorf("expected %d rows, got %d", len(people), count)
+		}
+
+		// a failing chunk must roll back the chunks before it
+		bad := []map[string]any{
+			{"first_name": "rollback", "last_name": "a", "email": "r@example.com"},
+			{"first_name": "rollback", "last_name": "b", "email": "r@example.com"},
+			{"first_name": "rollback", "last_name": "c"},
+		}
+		if _, err = db.NamedExecBatch(q, bad); err == nil {
+			t.Error("expected missing email to fail the batch")
+		}
+		db.Get(&count, "SELECT count(*) FROM person WHERE first_name = 'rollback'")
+		if count != 0 {
+			t.Errorf("expected batch to be rolled back, found %d rows", count)
+		}
+
+		tx := db.MustBegin()
+		n, err = tx.NamedExecBatch(q, [2]Person{{FirstName: "tx"}, {FirstName: "tx"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != 2 {
+			t.Errorf("expected 2 rows affected, got %d", n)
+		}
+		tx.Rollback()
+
+		BindParamLimit(db.DriverName(), 2)
+		if _, err = db.NamedExecBatch(q, people); err == nil {
+			t.Error("expected a query with more bindvars than the limit to fail")
+		}
+	})
+}
@@ -53,10 +53,9 @@ func BindDriver(driverName string, bindType int) {
 	binds.Store(driverName, bindType)
 }
 
-// FIXME: this should be able to be tolerant of escaped ?'s in queries without
-// losing much speed, and should be to avoid confusion.
-
 // Rebind a query from the default bindtype (QUESTION) to the target bindtype.
+// A `?` within a string literal, quoted identifier or comment is not a bindvar
+// and is left untouched.
 func Rebind(bindType int, query string) string {
 	switch bindType {
 	case QUESTION, UNKNOWN:
@@ -68,7 +67,7 @@ func Rebind(bindType int, query string) string {
 
 	var i, j int
 
-	for i = strings.Index(query, "?"); i != -1; i = strings.Index(query, "?") {
+	for i = nextBindVar(query, 0, false); i != -1; i = nextBindVar(query, 0, false) {
 		rqb = append(rqb, query[:i]...)
 
 		switch bindType {
@@ -119,6 +118,11 @@ func asSliceForIn(i any) (v reflect.Value, ok bool) {
 		return reflect.Value{}, false
 	}
 
+	// driver.Valuer types are values, and In uses what they return
+	if _, ok := i.(driver.Valuer); ok {
+		return reflect.Value{}, false
+	}
+
 	v = reflect.ValueOf(i)
 	t := reflectx.Deref(v.Type())
 
@@ -138,8 +142,19 @@ func asSliceForIn(i any) (v reflect.Value, ok bool) {
 
 // In expands slice values in args, returning the modified query string
 // and a new arg list that can be executed by a database. The `query` should
-// use the `?` bindVar.  The return value uses the `?` bindVar.
+// use the `?` bindVar.  The return value uses the `?` bindVar.  A `?` within a
+// string literal, quoted identifier or comment is not a bindVar.  The query is
+// lexed as standard SQL;  DB.In and Tx.In also honor the backslash escapes of
+// MySQL strings.
 func In(query string, args ...any) (string, []any, error) {
+	return in(query, args, false, false)
+}
+
+// in is In, where a backslash escapes a quote in any single quoted string of
+// query if backslashes is set.  If inListsOnly is set, only the slices whose
+// bindvar is the whole list of an IN are expanded, and other slices are passed
+// on as they are.
+func in(query string, args []any, backslashes, inListsOnly bool) (string, []any, error) {
 	// argMeta stores reflect.Value and length for slices and
 	// the value itself for non-slice arguments
 	type argMeta struct {
@@ -160,6 +175,11 @@ func In(query string, args ...any) (string, []any, error) {
 		meta = make([]argMeta, len(args))
 	}
 
+	var lists []bool
+	if inListsOnly {
+		lists = inLists(query, backslashes)
+	}
+
 	for i, arg := range args {
 		if a, ok := arg.(driver.Valuer); ok {
 			var err error
@@ -169,7 +189,7 @@ func In(query string, args ...any) (string, []any, error) {
 			}
 		}
 
-		if v, ok := asSliceForIn(arg); ok {
+		if v, ok := asSliceForIn(arg); ok && (!inListsOnly || i < len(lists) && lists[i]) {
 			meta[i].length = v.Len()
 			meta[i].v = v
 
@@ -198,7 +218,7 @@ func In(query string, args ...any) (string, []any, error) {
 
 	var arg, offset int
 
-	for i := strings.IndexByte(query[offset:], '?'); i != -1; i = strings.IndexByte(query[offset:], '?') {
+	for i := nextBindVar(query, offset, backslashes); i != -1; i = nextBindVar(query, offset, backslashes) {
 		if arg >= len(meta) {
 			// if an argument wasn't passed, lets return an error;  this is
 			// not actually how database/sql Exec/Query works, but since we are
@@ -214,13 +234,13 @@ func In(query string, args ...any) (string, []any, error) {
 		// our questionmark will either be written before the next expansion
 		// of a slice or after the loop when writing the rest of the query
 		if argMeta.length == 0 {
-			offset = offset + i + 1
+			offset = i + 1
 			newArgs = append(newArgs, argMeta.i)
 			continue
 		}
 
 		// write everything up to and including our ? character
-		buf.WriteString(query[:offset+i+1])
+		buf.WriteString(query[:i+1])
 
 		for si := 1; si < argMeta.length; si++ {
 			buf.WriteString(", ?")
@@ -230,7 +250,7 @@ func In(query string, args ...any) (string, []any, error) {
 
 		// slice the query and reset the offset. this avoids some bookkeeping for
 		// the write after the loop
-		query = query[offset+i+1:]
+		query = query[i+1:]
 		offset = 0
 	}
 
@@ -0,0 +1,492 @@
+// Package builder builds SELECT, INSERT, UPDATE and DELETE queries for sqlx.
+//
+// Conditions and values are written with the `?` bindvar.  Build rebinds the
+// query for a bindtype and expands slice arguments as sqlx.In does, so that
+// its output can be passed straight to sqlx:
+//
+//	q, args, err := builder.Select("id", "name").
+//		From("person").
+//		Where("age > ?", 18).
+//		In("country", []string{"NZ", "AU"}).
+//		OrderBy("name").
+//		Limit(10).
+//		Build(sqlx.BindType(db.DriverName()))
+//	...
+//	err = db.Select(&people, q, args...)
+//
+// BuildNamed instead returns a query with named parameters and a map of their
+// values, for NamedQuery and NamedExec.
+package builder
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/bitbus/sqlx"
+)
+
+// where is the WHERE clause shared by the builders.  It is the AND of its
+// terms, each of which is a condition or the OR of several.  Or replaces the
+// terms with a single term ORing all of them with its condition, so that Or
+// applies to everything before it, and a later Where is ANDed with the whole
+// expression, eg. Where(a).Or(b).Where(c) is `((a) OR (b)) AND (c)`.  Each
+// condition is parenthesized once it is combined with another, so that its
+// own operators can't bind to those of its neighbours.
+type where struct {
+	terms [][]string // the alternatives of an OR are parenthesized
+	args  []any
+}
+
+func (w *where) and(cond string, args []any) {
+	w.terms = append(w.terms, []string{cond})
+	w.args = append(w.args, args...)
+}
+
+func (w *where) or(cond string, args []any) {
+	switch {
+	case len(w.terms) == 0:
+		w.and(cond, args)
+		return
+	case len(w.terms) > 1:
+		// AND binds tighter than OR, and the terms are parenthesized
+		w.terms = [][]string{{w.expr()}}
+	case len(w.terms[0]) == 1:
+		w.terms[0][0] = "(" + w.terms[0][0] + ")"
+	}
+	w.terms[0] = append(w.terms[0], "("+cond+")")
+	w.args = append(w.args, args...)
+}
+
+// expr returns the terms ANDed together.
+func (w *where) expr() string {
+	if len(w.terms) == 1 {
+		return strings.Join(w.terms[0], " OR ")
+	}
+	terms := make([]string, len(w.terms))
+	for i, t := range w.terms {
+		terms[i] = "(" + strings.Join(t, " OR ") + ")"
+	}
+	return strings.Join(terms, " AND ")
+}
+
+func (w *where) write(b *strings.Builder) []any {
+	if len(w.terms) == 0 {
+		return nil
+	}
+	b.WriteString(" WHERE ")
+	b.WriteString(w.expr())
+	return w.args
+}
+
+func writeReturning(b *strings.Builder, columns []string) {
+	if len(columns) > 0 {
+		b.WriteString(" RETURNING ")
+		b.WriteString(strings.Join(columns, ", "))
+	}
+}
+
+// build expands the slices in args and rebinds query, which uses the `?`
+// bindvar, for bindType.
+func build(bindType int, query string, args []any, err error) (string, []any, error) {
+	if err != nil {
+		return "", nil, err
+	}
+	query, args, err = sqlx.In(query, args...)
+	if err != nil {
+		return "", nil, err
+	}
+	return sqlx.Rebind(bindType, query), args, nil
+}
+
+// buildNamed replaces each bindvar of query, which uses the `?` bindvar, with
+// a named parameter `:argN`, returning the query and the parameter values.
+// The colons of query, eg. those of `::` casts, are escaped for named queries
+// by sqlx.EscapeColons, and spaced from a parameter they follow, which would
+// otherwise read them as part of its name.  Slices are left for named queries
+// to expand.
+func buildNamed(query string, args []any, err error) (string, map[string]any, error) {
+	if err != nil {
+		return "", nil, err
+	}
+	query = sqlx.Rebind(sqlx.NAMED, sqlx.EscapeColons(query))
+	named := make(map[string]any, len(args))
+	for i, arg := range args {
+		name := ":arg" + strconv.Itoa(i+1)
+		query = strings.ReplaceAll(query, name+"::", name+" ::")
+		named[name[1:]] = arg
+	}
+	return query, named, nil
+}
+
+// SelectBuilder builds a SELECT query.
+type SelectBuilder struct {
+	columns []string
+	from    string
+	joins   []string
+	where   where
+	groupBy []string
+	orderBy []string
+	limit   int
+	offset  int
+	args    []any // of the joins
+}
+
+// Select starts a SELECT query of columns, or of `*` if there are none.
+func Select(columns ...string) *SelectBuilder {
+	return &SelectBuilder{columns: columns, limit: -1}
+}
+
+// From sets the table to select from.
+func (b *SelectBuilder) From(table string) *SelectBuilder {
+	b.from = table
+	return b
+}
+
+// Join adds an inner join of table on the condition on.
+func (b *SelectBuilder) Join(table, on string, args ...any) *SelectBuilder {
+	return b.join("JOIN", table, on, args)
+}
+
+// LeftJoin adds a left outer join of table on the condition on.
+func (b *SelectBuilder) LeftJoin(table, on string, args ...any) *SelectBuilder {
+	return b.join("LEFT JOIN", table, on, args)
+}
+
+func (b *SelectBuilder) join(kind, table, on string, args []any) *SelectBuilder {
+	b.joins = append(b.joins, kind+" "+table+" ON "+on)
+	b.args = append(b.args, args...)
+	return b
+}
+
+// Where adds a condition, which is ANDed with the conditions before it.
+func (b *SelectBuilder) Where(cond string, args ...any) *SelectBuilder {
+	b.where.and(cond, args)
+	return b
+}
+
+// And is an alias for Where.
+func (b *SelectBuilder) And(cond string, args ...any) *SelectBuilder {
+	return b.Where(cond, args...)
+}
+
+// Or adds a condition which is ORed with all of the conditions before it.
+// The conditions added after it are ANDed with the whole OR.
+func (b *SelectBuilder) Or(cond string, args ...any) *SelectBuilder {
+	b.where.or(cond, args)
+	return b
+}
+
+// In adds the condition that column is one of values, which is a slice.
+func (b *SelectBuilder) In(column string, values any) *SelectBuilder {
+	return b.Where(column+" IN (?)", values)
+}
+
+// GroupBy adds columns to group by.
+func (b *SelectBuilder) GroupBy(columns ...string) *SelectBuilder {
+	b.groupBy = append(b.groupBy, columns...)
+	return b
+}
+
+// OrderBy adds columns to order by, eg. "name DESC".
+func (b *SelectBuilder) OrderBy(columns ...string) *SelectBuilder {
+	b.orderBy = append(b.orderBy, columns...)
+	return b
+}
+
+// Limit sets the maximum number of rows to select.
+func (b *SelectBuilder) Limit(n int) *SelectBuilder {
+	b.limit = n
+	return b
+}
+
+// Offset sets the number of rows to skip.  Without a Limit, the query has
+// the largest limit which both MySQL and SQLite accept, as neither allows an
+// OFFSET without a LIMIT.
+func (b *SelectBuilder) Offset(n int) *SelectBuilder {
+	b.offset = n
+	return b
+}
+
+// sql returns the query with the `?` bindvar for bindType, which decides the
+// syntax of limits and offsets.
+func (b *SelectBuilder) sql(bindType int) (string, []any, error) {
+	if b.from == "" {
+		return "", nil, errors.New("builder: SELECT without a table")
+	}
+	var s strings.Builder
+	s.WriteString("SELECT ")
+	if len(b.columns) == 0 {
+		s.WriteString("*")
+	} else {
+		s.WriteString(strings.Join(b.columns, ", "))
+	}
+	s.WriteString(" FROM ")
+	s.WriteString(b.from)
+	for _, j := range b.joins {
+		s.WriteString(" ")
+		s.WriteString(j)
+	}
+	args := append(append([]any{}, b.args...), b.where.write(&s)...)
+	if len(b.groupBy) > 0 {
+		s.WriteString(" GROUP BY ")
+		s.WriteString(strings.Join(b.groupBy, ", "))
+	}
+	if len(b.orderBy) > 0 {
+		s.WriteString(" ORDER BY ")
+		s.WriteString(strings.Join(b.orderBy, ", "))
+	}
+
+	switch bindType {
+	case sqlx.AT, sqlx.NAMED:
+		// SQL Server and Oracle use the standard OFFSET and FETCH, which
+		// SQL Server only allows after an ORDER BY
+		if b.limit < 0 && b.offset == 0 {
+			break
+		}
+		if len(b.orderBy) == 0 && bindType == sqlx.AT {
+			s.WriteString(" ORDER BY (SELECT NULL)")
+		}
+		s.WriteString(" OFFSET " + strconv.Itoa(b.offset) + " ROWS")
+		if b.limit >= 0 {
+			s.WriteString(" FETCH NEXT " + strconv.Itoa(b.limit) + " ROWS ONLY")
+		}
+	default:
+		if b.limit >= 0 {
+			s.WriteString(" LIMIT " + strconv.Itoa(b.limit))
+		} else if b.offset > 0 && bindType != sqlx.DOLLAR {
+			s.WriteString(" LIMIT " + strconv.FormatInt(math.MaxInt64, 10))
+		}
+		if b.offset > 0 {
+			s.WriteString(" OFFSET " + strconv.Itoa(b.offset))
+		}
+	}
+	return s.String(), args, nil
+}
+
+// Build returns the query, rebound for bindType, and its arguments, with any
+// slices expanded.
+func (b *SelectBuilder) Build(bindType int) (string, []any, error) {
+	q, args, err := b.sql(bindType)
+	return build(bindType, q, args, err)
+}
+
+// BuildNamed returns the query with named parameters and their values.
+func (b *SelectBuilder) BuildNamed() (string, map[string]any, error) {
+	return buildNamed(b.sql(sqlx.QUESTION))
+}
+
+// InsertBuilder builds an INSERT query.
+type InsertBuilder struct {
+	table     string
+	columns   []string
+	rows      [][]any
+	returning []string
+}
+
+// Insert starts an INSERT query into table.
+func Insert(table string) *InsertBuilder {
+	return &InsertBuilder{table: table}
+}
+
+// Columns sets the columns to insert.
+func (b *InsertBuilder) Columns(columns ...string) *InsertBuilder {
+	b.columns = columns
+	return b
+}
+
+// Values adds a row of values, one for each of the columns.
+func (b *InsertBuilder) Values(values ...any) *InsertBuilder {
+	b.rows = append(b.rows, values)
+	return b
+}
+
+// Returning sets the columns returned by the query, for databases which
+// support RETURNING, such as postgres and sqlite.
+func (b *InsertBuilder) Returning(columns ...string) *InsertBuilder {
+	b.returning = columns
+	return b
+}
+
+func (b *InsertBuilder) sql() (string, []any, error) {
+	if len(b.columns) == 0 || len(b.rows) == 0 {
+		return "", nil, errors.New("builder: INSERT without columns or values")
+	}
+	var s strings.Builder
+	s.WriteString("INSERT INTO ")
+	s.WriteString(b.table)
+	s.WriteString(" (")
+	s.WriteString(strings.Join(b.columns, ", "))
+	s.WriteString(") VALUES ")
+	row := "(" + strings.Repeat("?, ", len(b.columns)-1) + "?)"
+	var args []any
+	for i, values := range b.rows {
+		if len(values) != len(b.columns) {
+			return "", nil, errors.New("builder: number of values does not match number of columns")
+		}
+		if i > 0 {
+			s.WriteString(", ")
+		}
+		s.WriteString(row)
+		args = append(args, values...)
+	}
+	writeReturning(&s, b.returning)
+	return s.String(), args, nil
+}
+
+// Build returns the query, rebound for bindType, and its arguments.
+func (b *InsertBuilder) Build(bindType int) (string, []any, error) {
+	q, args, err := b.sql()
+	return build(bindType, q, args, err)
+}
+
+// BuildNamed returns the query with named parameters and their values.
+func (b *InsertBuilder) BuildNamed() (string, map[string]any, error) {
+	return buildNamed(b.sql())
+}
+
+// UpdateBuilder builds an UPDATE query.
+type UpdateBuilder struct {
+	table     string
+	sets      []string
+	args      []any // of the sets
+	where     where
+	returning []string
+}
+
+// Update starts an UPDATE query of table.
+func Update(table string) *UpdateBuilder {
+	return &UpdateBuilder{table: table}
+}
+
+// Set sets column to value.
+func (b *UpdateBuilder) Set(column string, value any) *UpdateBuilder {
+	return b.SetExpr(column, "?", value)
+}
+
+// SetExpr sets column to the expression expr, eg. `count + ?`.
+func (b *UpdateBuilder) SetExpr(column, expr string, args ...any) *UpdateBuilder {
+	b.sets = append(b.sets, column+" = "+expr)
+	b.args = append(b.args, args...)
+	return b
+}
+
+// Where adds a condition, which is ANDed with the conditions before it.
+func (b *UpdateBuilder) Where(cond string, args ...any) *UpdateBuilder {
+	b.where.and(cond, args)
+	return b
+}
+
+// And is an alias for Where.
+func (b *UpdateBuilder) And(cond string, args ...any) *UpdateBuilder {
+	return b.Where(cond, args...)
+}
+
+// Or adds a condition which is ORed with all of the conditions before it.
+// The conditions added after it are ANDed with the whole OR.
+func (b *UpdateBuilder) Or(cond string, args ...any) *UpdateBuilder {
+	b.where.or(cond, args)
+	return b
+}
+
+// In adds the condition that column is one of values, which is a slice.
+func (b *UpdateBuilder) In(column string, values any) *UpdateBuilder {
+	return b.Where(column+" IN (?)", values)
+}
+
+// Returning sets the columns returned by the query, for databases which
+// support RETURNING, such as postgres and sqlite.
+func (b *UpdateBuilder) Returning(columns ...string) *UpdateBuilder {
+	b.returning = columns
+	return b
+}
+
+func (b *UpdateBuilder) sql() (string, []any, error) {
+	if len(b.sets) == 0 {
+		return "", nil, errors.New("builder: UPDATE without any columns to set")
+	}
+	var s strings.Builder
+	s.WriteString("UPDATE ")
+	s.WriteString(b.table)
+	s.WriteString(" SET ")
+	s.WriteString(strings.Join(b.sets, ", "))
+	args := append(append([]any{}, b.args...), b.where.write(&s)...)
+	writeReturning(&s, b.returning)
+	return s.String(), args, nil
+}
+
+// Build returns the query, rebound for bindType, and its arguments, with any
+// slices expanded.
+func (b *UpdateBuilder) Build(bindType int) (string, []any, error) {
+	q, args, err := b.sql()
+	return build(bindType, q, args, err)
+}
+
+// BuildNamed returns the query with named parameters and their values.
+func (b *UpdateBuilder) BuildNamed() (string, map[string]any, error) {
+	return buildNamed(b.sql())
+}
+
+// DeleteBuilder builds a DELETE query.
+type DeleteBuilder struct {
+	table     string
+	where     where
+	returning []string
+}
+
+// Delete starts a DELETE query from table.
+func Delete(table string) *DeleteBuilder {
+	return &DeleteBuilder{table: table}
+}
+
+// Where adds a condition, which is ANDed with the conditions before it.
+func (b *DeleteBuilder) Where(cond string, args ...any) *DeleteBuilder {
+	b.where.and(cond, args)
+	return b
+}
+
+// And is an alias for Where.
+func (b *DeleteBuilder) And(cond string, args ...any) *DeleteBuilder {
+	return b.Where(cond, args...)
+}
+
+// Or adds a condition which is ORed with all of the conditions before it.
+// The conditions added after it are ANDed with the whole OR.
+func (b *DeleteBuilder) Or(cond string, args ...any) *DeleteBuilder {
+	b.where.or(cond, args)
+	return b
+}
+
+// In adds the condition that column is one of values, which is a slice.
+func (b *DeleteBuilder) In(column string, values any) *DeleteBuilder {
+	return b.Where(column+" IN (?)", values)
+}
+
+// Returning sets the columns returned by the query, for databases which
+// support RETURNING, such as postgres and sqlite.
+func (b *DeleteBuilder) Returning(columns ...string) *DeleteBuilder {
+	b.returning = columns
+	return b
+}
+
+func (b *DeleteBuilder) sql() (string, []any, error) {
+	var s strings.Builder
+	s.WriteString("DELETE FROM ")
+	s.WriteString(b.table)
+	args := b.where.write(&s)
+	writeReturning(&s, b.returning)
+	return s.String(), args, nil
+}
+
+// Build returns the query, rebound for bindType, and its arguments, with any
+// slices expanded.
+func (b *DeleteBuilder) Build(bindType int) (string, []any, error) {
+	q, args, err := b.sql()
+	return build(bindType, q, args, err)
+}
+
+// BuildNamed returns the query with named parameters and their values.
+func (b *DeleteBuilder) BuildNamed() (string, map[string]any, error) {
+	return buildNamed(b.sql())
+}
@@ -0,0 +1,203 @@
+package builder
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bitbus/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestBuild(t *testing.T) {
+	var tests = []struct {
+		name string
+		b    interface {
+			Build(int) (string, []any, error)
+		}
+		bindType int
+		query    string
+		args     []any
+	}{
+		{
+			"select",
+			Select("p.id", "p.name").From("person p").
+				Join("place l", "l.id = p.place_id AND l.kind = ?", "city").
+				Where("p.age > ?", 18).
+				In("p.country", []string{"NZ", "AU"}).
+				Or("p.admin OR p.owner").
+				OrderBy("p.name").Limit(10).Offset(20),
+			sqlx.DOLLAR,
+			"SELECT p.id, p.name FROM person p JOIN place l ON l.id = p.place_id AND l.kind = $1 WHERE (p.age > $2) AND (p.country IN ($3, $4)) OR (p.admin OR p.owner) ORDER BY p.name LIMIT 10 OFFSET 20",
+			[]any{"city", 18, "NZ", "AU"},
+		},
+		{
+			"select offset",
+			Select().From("person").OrderBy("id").Offset(20),
+			sqlx.QUESTION,
+			"SELECT * FROM person ORDER BY id LIMIT 9223372036854775807 OFFSET 20",
+			[]any{},
+		},
+		{
+			"select offset postgres",
+			Select().From("person").OrderBy("id").Offset(20),
+			sqlx.DOLLAR,
+			"SELECT * FROM person ORDER BY id OFFSET 20",
+			[]any{},
+		},
+		{
+			"select or where",
+			Select().From("doc").Where("owner = ?", 1).Or("public").Where("tenant = ?", 2).Or("admin").Where("live"),
+			sqlx.QUESTION,
+			"SELECT * FROM doc WHERE (((owner = ?) OR (public)) AND (tenant = ?) OR (admin)) AND (live)",
+			[]any{1, 2},
+		},
+		{
+			"select multiline or",
+			Select().From("doc").Where("a = 1 or\nb = 2").Where("c = ?", 3),
+			sqlx.DOLLAR,
+			"SELECT * FROM doc WHERE (a = 1 or\nb = 2) AND (c = $1)",
+			[]any{3},
+		},
+		{
+			"select trailing backslash",
+			Select().From("t").Where(`path <> 'C:\'`).In("id", []int{1, 2}),
+			sqlx.DOLLAR,
+			`SELECT * FROM t WHERE (path <> 'C:\') AND (id IN ($1, $2))`,
+			[]any{1, 2},
+		},
+		{
+			"select sqlserver",
+			Select().From("person").Where("id = ?", 1).Limit(5),
+			sqlx.AT,
+			"SELECT * FROM person WHERE id = @p1 ORDER BY (SELECT NULL) OFFSET 0 ROWS FETCH NEXT 5 ROWS ONLY",
+			[]any{1},
+		},
+		{
+			"insert",
+			Insert("person").Columns("name", "age").Values("ann", 1).Values("bob", 2).Returning("id"),
+			sqlx.DOLLAR,
+			"INSERT INTO person (name, age) VALUES ($1, $2), ($3, $4) RETURNING id",
+			[]any{"ann", 1, "bob", 2},
+		},
+		{
+			"update",
+			Update("person").Set("name", "ann").SetExpr("visits", "visits + ?", 1).In("id", []int{1, 2}),
+			sqlx.QUESTION,
+			"UPDATE person SET name = ?, visits = visits + ? WHERE id IN (?, ?)",
+			[]any{"ann", 1, 1, 2},
+		},
+		{
+			"delete",
+			Delete("person").Where("age < ?", 18).And("name = ?", "ann"),
+			sqlx.AT,
+			"DELETE FROM person WHERE (age < @p1) AND (name = @p2)",
+			[]any{18, "ann"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			q, args, err := test.b.Build(test.bindType)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if q != test.query {
+				t.Errorf("expected %q, got %q", test.query, q)
+			}
+			if !reflect.DeepEqual(args, test.args) {
+				t.Errorf("expected args %v, got %v", test.args, args)
+			}
+		})
+	}
+}
+
+func TestBuildNamed(t *testing.T) {
+	q, args, err := Select("name").From("person").Where("age > ?", 18).In("id", []int{1, 2}).BuildNamed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q != "SELECT name FROM person WHERE (age > :arg1) AND (id IN (:arg2))" || args["arg1"] != 18 || len(args) != 2 {
+		t.Errorf("unexpected named query %q %v", q, args)
+	}
+
+	// the colons of casts, literals, comments and dollar-quoted strings
+	// survive the unescaping of `::` by named queries
+	q, args, err = Select("id::text", "'a::b'", "$$ :: $$").From("person").Where("name = ?::text /* :: */", "ann").BuildNamed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	q, bound, err := sqlx.Named(q, args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q != "SELECT id::text, 'a::b', $$ :: $$ FROM person WHERE name = ? ::text /* :: */" || len(bound) != 1 || bound[0] != "ann" {
+		t.Errorf("unexpected named query %q %v", q, bound)
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+	if _, _, err := Select().Build(sqlx.DOLLAR); err == nil {
+		t.Error("expected an error without a table")
+	}
+	if _, _, err := Insert("t").Columns("a", "b").Values(1).Build(sqlx.DOLLAR); err == nil {
+		t.Error("expected an error for a short row")
+	}
+	if _, _, err := Update("t").Build(sqlx.DOLLAR); err == nil {
+		t.Error("expected an error without columns to set")
+	}
+	if _, _, err := Select().From("t").In("id", []int{}).Build(sqlx.DOLLAR); err == nil {
+		t.Error("expected an error for an empty slice")
+	}
+}
+
+func TestBuildQuery(t *testing.T) {
+	db, err := sqlx.Connect("sqlite3", ":memory:")
+	if err != nil {
+		t.Skipf("sqlite3 unavailable: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	db.MustExec("CREATE TABLE person (id integer, name text)")
+	bindType := sqlx.BindType(db.DriverName())
+
+	q, args, err := Insert("person").Columns("id", "name").Values(1, "ann").Values(2, "bob").Values(3, "cid").Build(bindType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.MustExec(q, args...)
+
+	q, args, err = Select("name").From("person").In("id", []int{1, 3}).OrderBy("id DESC").Build(bindType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	if err = db.Select(&names, q, args...); err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 2 || names[0] != "cid" {
+		t.Errorf("unexpected names %v", names)
+	}
+
+	q, args, err = Select("name").From("person").OrderBy("id").Offset(1).Build(bindType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names = nil
+	if err = db.Select(&names, q, args...); err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 2 || names[0] != "bob" {
+		t.Errorf("unexpected names %v", names)
+	}
+
+	q, named, err := Delete("person").In("id", []int{1, 2}).BuildNamed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := db.NamedExec(q, named)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := res.RowsAffected(); n != 2 {
+		t.Errorf("expected 2 deleted rows, got %d", n)
+	}
+}
@@ -0,0 +1,352 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"sync/atomic"
+)
+
+var (
+	_ Queryable     = (*Cluster)(nil)
+	_ NamedBatcher  = (*Cluster)(nil)
+	_ NamedUpserter = (*Cluster)(nil)
+	_ Transactor    = (*Cluster)(nil)
+)
+
+// A Balancer picks which of a Cluster's replicas runs a read.  It is never
+// called with an empty slice of replicas.
+type Balancer interface {
+	Pick(replicas []*DB) *DB
+}
+
+// BalancerFunc adapts a function to a Balancer.
+type BalancerFunc func(replicas []*DB) *DB
+
+// Pick calls f(replicas).
+func (f BalancerFunc) Pick(replicas []*DB) *DB {
+	return f(replicas)
+}
+
+// RoundRobin returns a Balancer which picks each replica in turn.
+func RoundRobin() Balancer {
+	var next uint64
+	return BalancerFunc(func(replicas []*DB) *DB {
+		n := atomic.AddUint64(&next, 1) - 1
+		return replicas[n%uint64(len(replicas))]
+	})
+}
+
+// LeastConn returns a Balancer which picks the replica with the fewest
+// connections in use, as reported by sql.DB.Stats.
+func LeastConn() Balancer {
+	return BalancerFunc(func(replicas []*DB) *DB {
+		best, inUse := replicas[0], replicas[0].Stats().InUse
+		for _, r := range replicas[1:] {
+			if n := r.Stats().InUse; n < inUse {
+				best, inUse = r, n
+			}
+		}
+		return best
+	})
+}
+
+type primaryContextKey struct{}
+
+// WithPrimary returns a context in which a Cluster runs reads on its primary,
+// eg. to read a row right after writing it, before it reaches the replicas.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryContextKey{}, true)
+}
+
+func usePrimary(ctx context.Context) bool {
+	v, _ := ctx.Value(primaryContextKey{}).(bool)
+	return v
+}
+
+// Cluster is a Queryable over a primary DB and its read replicas.  Reads, ie.
+// Get, Select, Query and their variants, run on a replica picked by the
+// Cluster's Balancer, unless the context was made by WithPrimary.  Everything
+// else, including transactions, prepared statements and NamedQuery, which is
+// commonly used with `INSERT ... RETURNING`, runs on the primary.  A write
+// which returns rows through Queryx or QueryxContext must use the primary
+// explicitly, by context or through Primary.
+//
+// All of the DBs of a Cluster should use the same driver, as queries are bound
+// for the primary's.
+type Cluster struct {
+	primary  *DB
+	replicas []*DB
+	balancer Balancer
+}
+
+// NewCluster returns a Cluster over primary and replicas, which balances
+// reads over the replicas with RoundRobin.  Without replicas, reads run on the
+// primary.
+func NewCluster(primary *DB, replicas ...*DB) *Cluster {
+	return &Cluster{primary: primary, replicas: replicas, balancer: RoundRobin()}
+}
+
+// SetBalancer sets how the Cluster picks a replica for reads, in place of
+// RoundRobin.  Set it right after NewCluster, as swapping the Balancer of a
+// Cluster which is serving reads is a data race.
+func (c *Cluster) SetBalancer(b Balancer) {
+	c.balancer = b
+}
+
+// Primary returns the primary DB of the cluster.
+func (c *Cluster) Primary() *DB {
+	return c.primary
+}
+
+// Replicas returns the replica DBs of the cluster.
+func (c *Cluster) Replicas() []*DB {
+	return c.replicas
+}
+
+// Close closes the primary and all of the replicas, returning the first error.
+func (c *Cluster) Close() error {
+	errs := []error{c.primary.Close()}
+	for _, r := range c.replicas {
+		errs = append(errs, r.Close())
+	}
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// read returns the DB to run a read on.
+func (c *Cluster) read(ctx context.Context) *DB {
+	if len(c.replicas) == 0 || usePrimary(ctx) {
+		return c.primary
+	}
+	return c.balancer.Pick(c.replicas)
+}
+
+// DriverName returns the driverName of the primary.
+func (c *Cluster) DriverName() string {
+	return c.primary.DriverName()
+}
+
+// Rebind transforms a query from QUESTION to the primary's bindvar type.
+func (c *Cluster) Rebind(query string) string {
+	return c.primary.Rebind(query)
+}
+
+// BindNamed binds a query using the primary's bindvar type.
+func (c *Cluster) BindNamed(query string, arg any) (string, []any, error) {
+	return c.primary.BindNamed(query, arg)
+}
+
+// In expands slice values in args and rebinds the query for the primary.
+func (c *Cluster) In(query string, args ...any) (string, []any, error) {
+	return c.primary.In(query, args...)
+}
+
+// Query runs a query on a replica.
+func (c *Cluster) Query(query string, args ...any) (*sql.Rows, error) {
+	return c.read(context.Background()).Query(query, args...)
+}
+
+// QueryContext runs a query on a replica, or the primary if ctx was made by
+// WithPrimary.
+func (c *Cluster) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return c.read(ctx).QueryContext(ctx, query, args...)
+}
+
+// Queryx runs a query on a replica.
+func (c *Cluster) Queryx(query string, args ...any) (*Rows, error) {
+	return c.read(context.Background()).Queryx(query, args...)
+}
+
+// QueryxContext runs a query on a replica, or the primary if ctx was made by
+// WithPrimary.
+func (c *Cluster) QueryxContext(ctx context.Context, query string, args ...any) (*Rows, error) {
+	return c.read(ctx).QueryxContext(ctx, query, args...)
+}
+
+// QueryRow runs a query on a replica.
+func (c *Cluster) QueryRow(query string, args ...any) *sql.Row {
+	return c.read(context.Background()).QueryRow(query, args...)
+}
+
+// QueryRowContext runs a query on a replica, or the primary if ctx was made by
+// WithPrimary.
+func (c *Cluster) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return c.read(ctx).QueryRowContext(ctx, query, args...)
+}
+
+// QueryRowx runs a query on a replica.
+func (c *Cluster) QueryRowx(query string, args ...any) *Row {
+	return c.read(context.Background()).QueryRowx(query, args...)
+}
+
+// QueryRowxContext runs a query on a replica, or the primary if ctx was made by
+// WithPrimary.
+func (c *Cluster) QueryRowxContext(ctx context.Context, query string, args ...any) *Row {
+	return c.read(ctx).QueryRowxContext(ctx, query, args...)
+}
+
+// Get runs a query on a replica and scans the row into dest.
+func (c *Cluster) Get(dest any, query string, args ...any) error {
+	return c.read(context.Background()).Get(dest, query, args...)
+}
+
+// GetContext runs a query on a replica, or the primary if ctx was made by
+// WithPrimary, and scans the row into dest.
+func (c *Cluster) GetContext(ctx context.Context, dest any, query string, args ...any) error {
+	return c.read(ctx).GetContext(ctx, dest, query, args...)
+}
+
+// Select runs a query on a replica and scans the rows into dest.
+func (c *Cluster) Select(dest any, query string, args ...any) error {
+	return c.read(context.Background()).Select(dest, query, args...)
+}
+
+// SelectContext runs a query on a replica, or the primary if ctx was made by
+// WithPrimary, and scans the rows into dest.
+func (c *Cluster) SelectContext(ctx context.Context, dest any, query string, args ...any) error {
+	return c.read(ctx).SelectContext(ctx, dest, query, args...)
+}
+
+// InGet expands slice values in args and runs Get on a replica.
+func (c *Cluster) InGet(dest any, query string, args ...any) error {
+	return c.read(context.Background()).InGet(dest, query, args...)
+}
+
+// InSelect expands slice values in args and runs Select on a replica.
+func (c *Cluster) InSelect(dest any, query string, args ...any) error {
+	return c.read(context.Background()).InSelect(dest, query, args...)
+}
+
+// Exec runs a query on the primary.
+func (c *Cluster) Exec(query string, args ...any) (sql.Result, error) {
+	return c.primary.Exec(query, args...)
+}
+
+// ExecContext runs a query on the primary.
+func (c *Cluster) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return c.primary.ExecContext(ctx, query, args...)
+}
+
+// MustExec runs a query on the primary, panicking on error.
+func (c *Cluster) MustExec(query string, args ...any) sql.Result {
+	return c.primary.MustExec(query, args...)
+}
+
+// MustExecContext runs a query on the primary, panicking on error.
+func (c *Cluster) MustExecContext(ctx context.Context, query string, args ...any) sql.Result {
+	return c.primary.MustExecContext(ctx, query, args...)
+}
+
+// InExec expands slice values in args and runs Exec on the primary.
+func (c *Cluster) InExec(query string, args ...any) (sql.Result, error) {
+	return c.primary.InExec(query, args...)
+}
+
+// MustInExec expands slice values in args and runs Exec on the primary,
+// panicking on error.
+func (c *Cluster) MustInExec(query string, args ...any) sql.Result {
+	return c.primary.MustInExec(query, args...)
+}
+
+// NamedExec runs a named query on the primary.
+func (c *Cluster) NamedExec(query string, arg any) (sql.Result, error) {
+	return c.primary.NamedExec(query, arg)
+}
+
+// NamedExecContext runs a named query on the primary.
+func (c *Cluster) NamedExecContext(ctx context.Context, query string, arg any) (sql.Result, error) {
+	return c.primary.NamedExecContext(ctx, query, arg)
+}
+
+// NamedExecBatch runs a batch insert on the primary.
+func (c *Cluster) NamedExecBatch(query string, arg any) (int64, error) {
+	return c.primary.NamedExecBatch(query, arg)
+}
+
+// NamedExecBatchContext runs a batch insert on the primary.
+func (c *Cluster) NamedExecBatchContext(ctx context.Context, query string, arg any) (int64, error) {
+	return c.primary.NamedExecBatchContext(ctx, query, arg)
+}
+
+// NamedUpsert runs an upsert on the primary.
+func (c *Cluster) NamedUpsert(table string, arg any, keys ...string) (sql.Result, error) {
+	return c.primary.NamedUpsert(table, arg, keys...)
+}
+
+// NamedUpsertContext runs an upsert on the primary.
+func (c *Cluster) NamedUpsertContext(ctx context.Context, table string, arg any, keys ...string) (sql.Result, error) {
+	return c.primary.NamedUpsertContext(ctx, table, arg, keys...)
+}
+
+// NamedQuery runs a named query on the primary.
+func (c *Cluster) NamedQuery(query string, arg any) (*Rows, error) {
+	return c.primary.NamedQuery(query, arg)
+}
+
+// Prepare prepares a statement on the primary.
+func (c *Cluster) Prepare(query string) (*sql.Stmt, error) {
+	return c.primary.Prepare(query)
+}
+
+// PrepareContext prepares a statement on the primary.
+func (c *Cluster) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return c.primary.PrepareContext(ctx, query)
+}
+
+// Preparex prepares a statement on the primary.
+func (c *Cluster) Preparex(query string) (*Stmt, error) {
+	return c.primary.Preparex(query)
+}
+
+// PreparexContext prepares a statement on the primary.
+func (c *Cluster) PreparexContext(ctx context.Context, query string) (*Stmt, error) {
+	return c.primary.PreparexContext(ctx, query)
+}
+
+// PrepareNamed prepares a named statement on the primary.
+func (c *Cluster) PrepareNamed(query string) (*NamedStmt, error) {
+	return c.primary.PrepareNamed(query)
+}
+
+// PrepareNamedContext prepares a named statement on the primary.
+func (c *Cluster) PrepareNamedContext(ctx context.Context, query string) (*NamedStmt, error) {
+	return c.primary.PrepareNamedContext(ctx, query)
+}
+
+// Beginx begins a transaction on the primary.
+func (c *Cluster) Beginx() (*Tx, error) {
+	return c.primary.Beginx()
+}
+
+// BeginTxx begins a transaction on the primary.
+func (c *Cluster) BeginTxx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	return c.primary.BeginTxx(ctx, opts)
+}
+
+// Withx runs handle within a transaction on the primary.
+func (c *Cluster) Withx(handle func(tx *Tx) error) error {
+	return c.primary.Withx(handle)
+}
+
+// WithTxx runs handle within a transaction on the primary.
+func (c *Cluster) WithTxx(ctx context.Context, opts *sql.TxOptions, handle func(tx *Tx) error) error {
+	return c.primary.WithTxx(ctx, opts, handle)
+}
+
+// PingContext verifies that the primary and every replica are reachable,
+// returning the first error.
+func (c *Cluster) PingContext(ctx context.Context) error {
+	if err := c.primary.PingContext(ctx); err != nil {
+		return err
+	}
+	for _, r := range c.replicas {
+		if err := r.PingContext(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
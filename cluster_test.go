@@ -0,0 +1,74 @@
+package sqlx
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCluster(t *testing.T) {
+	open := func(name string) *DB {
+		db, err := Connect("sqlite3", ":memory:")
+		if err != nil {
+			t.Fatal(err)
+		}
+		db.SetMaxOpenConns(1)
+		db.MustExec("CREATE TABLE node (name text)")
+		db.MustExec("INSERT INTO node (name) VALUES (?)", name)
+		return db
+	}
+	c := NewCluster(open("primary"), open("r1"), open("r2"))
+	defer c.Close()
+	ctx := context.Background()
+
+	var names []string
+	for i := 0; i < 4; i++ {
+		var name string
+		if err := c.GetContext(ctx, &name, "SELECT name FROM node"); err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, name)
+	}
+	if names[0] != "r1" || names[1] != "r2" || names[2] != "r1" || names[3] != "r2" {
+		t.Errorf("expected reads to alternate between the replicas, got %v", names)
+	}
+
+	// writes, transactions and forced reads use the primary
+	c.MustExec("INSERT INTO node (name) VALUES (?)", "written")
+	var n int
+	if err := c.Get(&n, "SELECT count(*) FROM node"); err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("expected the replica not to see the write, got %d rows", n)
+	}
+	if err := c.GetContext(WithPrimary(ctx), &n, "SELECT count(*) FROM node"); err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("expected the primary to see the write, got %d rows", n)
+	}
+	err := c.Withx(func(tx *Tx) error {
+		return tx.Get(&n, "SELECT count(*) FROM node")
+	})
+	if err != nil || n != 2 {
+		t.Errorf("expected the transaction to run on the primary, got %d rows, %v", n, err)
+	}
+
+	c.SetBalancer(LeastConn())
+	rows, err := c.Queryx("SELECT name FROM node")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// r1 holds its only connection until rows is closed
+	if err = c.Select(&names, "SELECT name FROM node"); err != nil {
+		t.Fatal(err)
+	}
+	rows.Close()
+	if len(names) != 1 || names[0] != "r2" {
+		t.Errorf("expected the least busy replica to be used, got %v", names)
+	}
+
+	if NewCluster(c.Primary()).read(ctx) != c.Primary() {
+		t.Error("expected reads to use the primary without replicas")
+	}
+}
@@ -8,6 +8,16 @@ import (
 var (
 	_ Queryable = (*DB)(nil)
 	_ Queryable = (*Tx)(nil)
+
+	_ NamedBatcher = (*DB)(nil)
+	_ NamedBatcher = (*Tx)(nil)
+
+	_ NamedUpserter = (*DB)(nil)
+	_ NamedUpserter = (*Tx)(nil)
+
+	_ Transactor = (*DB)(nil)
+	_ Transactor = (*Tx)(nil)
+	_ Transactor = (*Conn)(nil)
 )
 
 // Queryable includes all methods shared by sqlx.DB and sqlx.Tx, allowing
@@ -41,3 +51,26 @@ type Queryable interface {
 	InExec(string, ...any) (sql.Result, error)
 	MustInExec(string, ...any) sql.Result
 }
+
+// NamedBatcher is implemented by DB, Tx and Cluster, which can run
+// NamedExecBatch.  It is not part of Queryable, so that the implementations of
+// Queryable outside of sqlx don't have to add it.
+type NamedBatcher interface {
+	NamedExecBatch(string, interface{}) (int64, error)
+	NamedExecBatchContext(context.Context, string, interface{}) (int64, error)
+}
+
+// NamedUpserter is implemented by DB, Tx and Cluster, which can run
+// NamedUpsert.  Like NamedBatcher, it is kept apart from Queryable.
+type NamedUpserter interface {
+	NamedUpsert(string, interface{}, ...string) (sql.Result, error)
+	NamedUpsertContext(context.Context, string, interface{}, ...string) (sql.Result, error)
+}
+
+// Transactor is implemented by DB, Conn, Tx and Cluster, which can run a
+// function within a transaction.  A Tx runs it within a savepoint instead.
+// Like NamedBatcher, it is kept apart from Queryable.
+type Transactor interface {
+	Withx(func(*Tx) error) error
+	WithTxx(context.Context, *sql.TxOptions, func(*Tx) error) error
+}
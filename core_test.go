@@ -34,6 +34,13 @@ func TestQueryable(t *testing.T) {
 
 	queryableType := reflect.TypeOf((*Queryable)(nil)).Elem()
 	queryableMethods := exportableMethods(queryableType)
+	// methods added since are in interfaces of their own, so that adding them
+	// doesn't break the implementations of Queryable outside of sqlx
+	for _, i := range []any{(*NamedBatcher)(nil), (*NamedUpserter)(nil), (*Transactor)(nil)} {
+		for k, v := range exportableMethods(reflect.TypeOf(i).Elem()) {
+			queryableMethods[k] = v
+		}
+	}
 
 	for _, sharedMethodName := range sharedMethods {
 		if _, ok := queryableMethods[sharedMethodName]; !ok {
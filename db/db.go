@@ -36,17 +36,15 @@ func GetContext[T any](ctx context.Context, stmt *sqlx.Stmt, args ...any) (dest
 
 // Select[T] using the prepared statement.
 // Any placeholder parameters are replaced with supplied args.
-func Select[T any](stmt *sqlx.Stmt, args ...any) (dest *T, err error) {
-	dest = new(T)
-	err = stmt.Select(dest, args...)
+func Select[T any](stmt *sqlx.Stmt, args ...any) (dest []T, err error) {
+	err = stmt.Select(&dest, args...)
 	return
 }
 
 // SelectContext[T] using the prepared statement.
 // Any placeholder parameters are replaced with supplied args.
-func SelectContext[T any](ctx context.Context, stmt *sqlx.Stmt, args ...any) (dest *T, err error) {
-	dest = new(T)
-	err = stmt.SelectContext(ctx, dest, args...)
+func SelectContext[T any](ctx context.Context, stmt *sqlx.Stmt, args ...any) (dest []T, err error) {
+	err = stmt.SelectContext(ctx, &dest, args...)
 	return
 }
 
@@ -68,17 +66,15 @@ func NamedGetContext[T any](ctx context.Context, stmt *sqlx.NamedStmt, arg any)
 
 // NamedSelect using this NamedStmt
 // Any named placeholder parameters are replaced with fields from arg.
-func NamedSelect[T any](stmt *sqlx.NamedStmt, arg any) (dest *T, err error) {
-	dest = new(T)
-	err = stmt.Select(dest, arg)
+func NamedSelect[T any](stmt *sqlx.NamedStmt, arg any) (dest []T, err error) {
+	err = stmt.Select(&dest, arg)
 	return
 }
 
 // NamedSelectContext using this NamedStmt
 // Any named placeholder parameters are replaced with fields from arg.
-func NamedSelectContext[T any](ctx context.Context, stmt *sqlx.NamedStmt, arg any) (dest *T, err error) {
-	dest = new(T)
-	err = stmt.SelectContext(ctx, dest, arg)
+func NamedSelectContext[T any](ctx context.Context, stmt *sqlx.NamedStmt, arg any) (dest []T, err error) {
+	err = stmt.SelectContext(ctx, &dest, arg)
 	return
 }
 
@@ -89,17 +85,101 @@ func NamedSelectContext[T any](ctx context.Context, stmt *sqlx.NamedStmt, arg an
 // An error is returned if the result set is empty.
 func InGet[T any](q sqlx.Queryable, query string, args ...any) (dest *T, err error) {
 	dest = new(T)
-	err = q.InGet(*dest, query, args...)
+	err = q.InGet(dest, query, args...)
 	return
 }
 
 // InSelect[T] for in scene executes a query using the provided Queryer, and StructScans each row
-// into dest, which must be a slice.  If the slice elements are scannable, then
+// into the returned slice.  If the slice elements are scannable, then
 // the result set must have only one column.  Otherwise, StructScan is used.
 // The *sql.Rows are closed automatically.
 // Any placeholder parameters are replaced with supplied args.
-func InSelect[T any](q sqlx.Queryable, query string, args ...any) (dest *T, err error) {
-	dest = new(T)
-	err = q.InSelect(dest, query, args...)
+func InSelect[T any](q sqlx.Queryable, query string, args ...any) (dest []T, err error) {
+	err = q.InSelect(&dest, query, args...)
+	return
+}
+
+// QueryGet[T] does a QueryRow using the provided Queryer, and scans the resulting
+// row into a T.  If T is scannable, the result must only have one column.
+// Otherwise, StructScan is used.  QueryGet[T] will return sql.ErrNoRows like
+// row.Scan would.
+// Any placeholder parameters are replaced with supplied args.
+func QueryGet[T any](q sqlx.Queryer, query string, args ...any) (dest T, err error) {
+	err = sqlx.Get(q, &dest, query, args...)
+	return
+}
+
+// QueryGetContext[T] does a QueryRow using the provided QueryerContext, and
+// scans the resulting row into a T.  If T is scannable, the result must only
+// have one column.  Otherwise, StructScan is used.  QueryGetContext[T] will
+// return sql.ErrNoRows like row.Scan would.
+// Any placeholder parameters are replaced with supplied args.
+func QueryGetContext[T any](ctx context.Context, q sqlx.QueryerContext, query string, args ...any) (dest T, err error) {
+	err = sqlx.GetContext(ctx, q, &dest, query, args...)
+	return
+}
+
+// QuerySelect[T] executes a query using the provided Queryer, and StructScans
+// each row into the returned slice.  If T is scannable, then the result set
+// must have only one column.  Otherwise, StructScan is used.
+// The *sql.Rows are closed automatically.
+// Any placeholder parameters are replaced with supplied args.
+func QuerySelect[T any](q sqlx.Queryer, query string, args ...any) (dest []T, err error) {
+	err = sqlx.Select(q, &dest, query, args...)
 	return
 }
+
+// QuerySelectContext[T] executes a query using the provided QueryerContext, and
+// StructScans each row into the returned slice.  If T is scannable, then the
+// result set must have only one column.  Otherwise, StructScan is used.
+// The *sql.Rows are closed automatically.
+// Any placeholder parameters are replaced with supplied args.
+func QuerySelectContext[T any](ctx context.Context, q sqlx.QueryerContext, query string, args ...any) (dest []T, err error) {
+	err = sqlx.SelectContext(ctx, q, &dest, query, args...)
+	return
+}
+
+// NamedQueryGet[T] binds a named query using the provided Ext and scans the
+// resulting row into a T, like QueryGet[T].
+// Any named placeholder parameters are replaced with fields from arg.
+func NamedQueryGet[T any](q sqlx.Ext, query string, arg any) (dest T, err error) {
+	bound, args, err := q.BindNamed(query, arg)
+	if err != nil {
+		return dest, err
+	}
+	return QueryGet[T](q, bound, args...)
+}
+
+// NamedQueryGetContext[T] binds a named query using the provided ExtContext and
+// scans the resulting row into a T, like QueryGetContext[T].
+// Any named placeholder parameters are replaced with fields from arg.
+func NamedQueryGetContext[T any](ctx context.Context, q sqlx.ExtContext, query string, arg any) (dest T, err error) {
+	bound, args, err := q.BindNamed(query, arg)
+	if err != nil {
+		return dest, err
+	}
+	return QueryGetContext[T](ctx, q, bound, args...)
+}
+
+// NamedQuerySelect[T] binds a named query using the provided Ext and scans all
+// of the resulting rows into the returned slice, like QuerySelect[T].
+// Any named placeholder parameters are replaced with fields from arg.
+func NamedQuerySelect[T any](q sqlx.Ext, query string, arg any) (dest []T, err error) {
+	bound, args, err := q.BindNamed(query, arg)
+	if err != nil {
+		return nil, err
+	}
+	return QuerySelect[T](q, bound, args...)
+}
+
+// NamedQuerySelectContext[T] binds a named query using the provided ExtContext
+// and scans all of the resulting rows into the returned slice, like
+// QuerySelectContext[T].
+// Any named placeholder parameters are replaced with fields from arg.
+func NamedQuerySelectContext[T any](ctx context.Context, q sqlx.ExtContext, query string, arg any) (dest []T, err error) {
+	bound, args, err := q.BindNamed(query, arg)
+	if err != nil {
+		return nil, err
+	}
+	return QuerySelectContext[T](ctx, q, bound, args...)
+}
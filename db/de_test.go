@@ -1,9 +1,93 @@
 package db
 
 import (
+	"context"
+	"database/sql"
 	"testing"
 )
 
 func TestUsage(t *testing.T) {
-	// TODO: add usage test case here.
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	p, err := QueryGet[person](db, db.Rebind("SELECT id, name FROM person WHERE id = ?"), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "bob" {
+		t.Errorf("expected bob, got %#v", p)
+	}
+
+	n, err := QueryGetContext[int](ctx, db, "SELECT count(*) FROM person")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 4 {
+		t.Errorf("expected 4 people, got %d", n)
+	}
+
+	if _, err = QueryGet[person](db, "SELECT id, name FROM person WHERE id = 42"); err != sql.ErrNoRows {
+		t.Errorf("expected sql.ErrNoRows, got %v", err)
+	}
+
+	people, err := QuerySelect[person](db, "SELECT id, name FROM person ORDER BY id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(people) != 4 || people[3].Name != "dee" {
+		t.Errorf("unexpected people: %#v", people)
+	}
+
+	names, err := QuerySelectContext[string](ctx, db, "SELECT name FROM person WHERE id > ? ORDER BY id", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 2 || names[0] != "cid" {
+		t.Errorf("unexpected names: %#v", names)
+	}
+
+	p, err = NamedQueryGet[person](db, "SELECT id, name FROM person WHERE name = :name", map[string]any{"name": "cid"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.ID != 3 {
+		t.Errorf("expected id 3, got %#v", p)
+	}
+
+	ptrs, err := NamedQuerySelectContext[*person](ctx, db, "SELECT id, name FROM person WHERE id <= :id ORDER BY id", person{ID: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ptrs) != 2 || ptrs[1].Name != "bob" {
+		t.Errorf("unexpected people: %#v", ptrs)
+	}
+
+	stmt, err := db.Preparex("SELECT id, name FROM person WHERE id > ? ORDER BY id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+	people, err = Select[person](stmt, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(people) != 3 {
+		t.Errorf("expected 3 people, got %d", len(people))
+	}
+
+	in, err := InGet[person](db, "SELECT id, name FROM person WHERE id IN (?)", []int{4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if in.Name != "dee" {
+		t.Errorf("expected dee, got %#v", in)
+	}
+
+	people, err = InSelect[person](db, "SELECT id, name FROM person WHERE id IN (?) ORDER BY id", []int{1, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(people) != 2 || people[1].Name != "cid" {
+		t.Errorf("unexpected people: %#v", people)
+	}
 }
@@ -0,0 +1,174 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+
+	"github.com/bitbus/sqlx"
+	"github.com/bitbus/sqlx/reflectx"
+)
+
+var _scannerInterface = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+// Iterator[T] decodes an sqlx.Rows one row at a time into values of type T.
+// Unlike Select[T], which loads the entire result set into memory, an
+// Iterator[T] only ever holds the current row, which makes it suitable for
+// streaming very large result sets.
+//
+// The struct field traversals are computed from the row columns once, on the
+// first call to Next, and reused for every following row.
+//
+// An Iterator[T] closes its rows once they are exhausted, when an error is
+// encountered or when its context is done.  Callers which stop iterating
+// early must call Close, which is safe to call more than once:
+//
+//	it, err := db.Iter[Person](ctx, q, "SELECT * FROM person")
+//	if err != nil {
+//		return err
+//	}
+//	defer it.Close()
+//	for it.Next() {
+//		p := it.Value()
+//		...
+//	}
+//	return it.Err()
+type Iterator[T any] struct {
+	ctx    context.Context
+	rows   *sqlx.Rows
+	value  T
+	err    error
+	closed bool
+
+	// these fields cache the reflect work done on the first row
+	started   bool
+	isPtr     bool
+	base      reflect.Type
+	scannable bool
+}
+
+// Iter[T] executes a query using the provided QueryerContext and returns an
+// Iterator[T] over the resulting rows.
+// Any placeholder parameters are replaced with supplied args.
+func Iter[T any](ctx context.Context, q sqlx.QueryerContext, query string, args ...any) (*Iterator[T], error) {
+	rows, err := q.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return IterRows[T](ctx, rows), nil
+}
+
+// IterRows[T] returns an Iterator[T] over already queried rows.  The iterator
+// takes ownership of rows and closes them when iteration finishes.
+func IterRows[T any](ctx context.Context, rows *sqlx.Rows) *Iterator[T] {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return &Iterator[T]{ctx: ctx, rows: rows}
+}
+
+// Each[T] executes a query using the provided QueryerContext and calls fn for
+// every row decoded into a T.  Iteration stops at the first error returned by
+// fn, which is returned to the caller.  The rows are always closed before
+// Each[T] returns.
+// Any placeholder parameters are replaced with supplied args.
+func Each[T any](ctx context.Context, q sqlx.QueryerContext, fn func(T) error, query string, args ...any) error {
+	it, err := Iter[T](ctx, q, query, args...)
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	for it.Next() {
+		if err = fn(it.Value()); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
+
+// Next prepares the next row to be read with Value.  It returns false when
+// the rows are exhausted, the context is done or an error occurred, in
+// which case the rows are closed and Err reports the reason.
+func (it *Iterator[T]) Next() bool {
+	if it.closed {
+		return false
+	}
+	if err := it.ctx.Err(); err != nil {
+		it.fail(err)
+		return false
+	}
+	if !it.rows.Next() {
+		it.fail(it.rows.Err())
+		return false
+	}
+	if err := it.scan(); err != nil {
+		it.fail(err)
+		return false
+	}
+	return true
+}
+
+// Value returns the row decoded by the last successful call to Next.
+func (it *Iterator[T]) Value() T {
+	return it.value
+}
+
+// Err returns the error, if any, that was encountered during iteration.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// Close closes the underlying rows.  It is safe to call Close multiple times
+// and after the iterator has been exhausted.
+func (it *Iterator[T]) Close() error {
+	if it.closed {
+		return nil
+	}
+	it.closed = true
+	return it.rows.Close()
+}
+
+func (it *Iterator[T]) fail(err error) {
+	if it.err == nil {
+		it.err = err
+	}
+	it.Close()
+}
+
+func (it *Iterator[T]) scan() error {
+	if !it.started {
+		t := reflect.TypeOf((*T)(nil)).Elem()
+		it.isPtr = t.Kind() == reflect.Ptr
+		it.base = reflectx.Deref(t)
+		it.scannable = isScannable(it.rows.Mapper, it.base)
+		it.started = true
+	}
+
+	var zero T
+	it.value = zero
+
+	vp := reflect.ValueOf(&it.value)
+	if it.isPtr {
+		vp.Elem().Set(reflect.New(it.base))
+		vp = vp.Elem()
+	}
+	if it.scannable {
+		return it.rows.Scan(vp.Interface())
+	}
+	// sqlx.Rows.StructScan caches the traversals for the columns on the first
+	// call, so subsequent rows only pay for the field lookups and the scan.
+	return it.rows.StructScan(vp.Interface())
+}
+
+// isScannable mirrors the rules sqlx uses to decide whether a type is scanned
+// directly or field by field: it is if it implements sql.Scanner, is not a
+// struct, or is a struct without exported fields.
+func isScannable(m *reflectx.Mapper, t reflect.Type) bool {
+	if reflect.PtrTo(t).Implements(_scannerInterface) {
+		return true
+	}
+	if t.Kind() != reflect.Struct {
+		return true
+	}
+	return len(m.TypeMap(t).Index) == 0
+}
@@ -0,0 +1,142 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bitbus/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type person struct {
+	ID   int    `db:"id"`
+	Name string `db:"name"`
+}
+
+func openTestDB(t *testing.T) *sqlx.DB {
+	t.Helper()
+	db, err := sqlx.Connect("sqlite3", ":memory:")
+	if err != nil {
+		t.Skipf("sqlite3 unavailable: %v", err)
+	}
+	// every connection to :memory: is a separate database
+	db.SetMaxOpenConns(1)
+	db.MustExec(`CREATE TABLE person (id integer, name text)`)
+	for i, name := range []string{"ann", "bob", "cid", "dee"} {
+		db.MustExec(`INSERT INTO person (id, name) VALUES (?, ?)`, i+1, name)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestIter(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	it, err := Iter[person](ctx, db, "SELECT id, name FROM person ORDER BY id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var people []person
+	for it.Next() {
+		people = append(people, it.Value())
+	}
+	if err = it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(people) != 4 || people[0].Name != "ann" || people[3].ID != 4 {
+		t.Errorf("unexpected rows: %#v", people)
+	}
+
+	ptrs, err := Iter[*person](ctx, db, "SELECT id, name FROM person ORDER BY id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var prev *person
+	for ptrs.Next() {
+		p := ptrs.Value()
+		if p == prev {
+			t.Error("expected a new pointer for every row")
+		}
+		prev = p
+	}
+	if prev == nil || prev.Name != "dee" {
+		t.Errorf("unexpected last row: %#v", prev)
+	}
+
+	ids, err := Iter[int](ctx, db, "SELECT id FROM person ORDER BY id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := 0
+	for ids.Next() {
+		sum += ids.Value()
+	}
+	if sum != 10 {
+		t.Errorf("expected sum of ids to be 10, got %d", sum)
+	}
+}
+
+func TestIterEarlyBreak(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	it, err := Iter[person](ctx, db, "SELECT id, name FROM person ORDER BY id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !it.Next() {
+		t.Fatal(it.Err())
+	}
+	if err = it.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if it.Next() {
+		t.Error("expected Next to return false after Close")
+	}
+	if err = it.Close(); err != nil {
+		t.Errorf("expected a second Close to succeed, got %v", err)
+	}
+
+	// with a single connection, leaked rows would block this query forever
+	var n int
+	if err = db.Get(&n, "SELECT count(*) FROM person"); err != nil {
+		t.Fatal(err)
+	}
+
+	stop := errors.New("stop")
+	var seen []string
+	err = Each(ctx, db, func(p person) error {
+		seen = append(seen, p.Name)
+		if len(seen) == 2 {
+			return stop
+		}
+		return nil
+	}, "SELECT id, name FROM person ORDER BY id")
+	if err != stop {
+		t.Errorf("expected stop error, got %v", err)
+	}
+	if len(seen) != 2 {
+		t.Errorf("expected 2 rows before stopping, got %d", len(seen))
+	}
+}
+
+func TestIterContextCancel(t *testing.T) {
+	db := openTestDB(t)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	it, err := Iter[person](ctx, db, "SELECT id, name FROM person ORDER BY id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !it.Next() {
+		t.Fatal(it.Err())
+	}
+	cancel()
+	for it.Next() {
+	}
+	if !errors.Is(it.Err(), context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", it.Err())
+	}
+}
@@ -0,0 +1,235 @@
+package db
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/bitbus/sqlx"
+	"github.com/bitbus/sqlx/reflectx"
+)
+
+// ErrInvalidCursor is returned by Paginator.Fetch for a cursor which it did
+// not produce.
+var ErrInvalidCursor = errors.New("sqlx.db: invalid page cursor")
+
+// Page[T] is a page of rows fetched by a Paginator.  Next and Prev are the
+// cursors of the following and preceding pages, and are empty if there is no
+// such page.
+type Page[T any] struct {
+	Items []T
+	Next  string
+	Prev  string
+}
+
+// cursor is the decoded form of a page cursor: the sort key values of the row
+// the page starts after, or before if prev is set.
+type cursor struct {
+	Prev bool              `json:"p,omitempty"`
+	Keys []json.RawMessage `json:"k"`
+}
+
+// Paginator[T] fetches the rows of a query a page at a time with keyset
+// pagination:  rather than skipping rows with an OFFSET, each page selects
+// the rows which sort after the last row of the page before it,
+//
+//	SELECT * FROM (<query>) AS sqlx_page WHERE (a, b) > (?, ?) ORDER BY a, b LIMIT ?
+//
+// so that fetching a page stays fast however deep into the results it is,
+// given an index on the sort columns.  The sort columns must be result columns
+// of the query which are mapped to fields of T and which together identify a
+// row, eg. ending with the primary key.  A page's cursors carry the sort key
+// values of its first and last rows, read from those fields, and are opaque to
+// clients:
+//
+//	p, err := db.NewPaginator[Person]("SELECT * FROM person WHERE age > ?", 20, "name", "id")
+//	...
+//	page, err := p.Fetch(ctx, q, r.URL.Query().Get("cursor"), 18)
+//
+// The rows are sorted by the columns in ascending order, or descending order
+// if each of them has a DESC suffix.  Paginator[T] uses LIMIT, so it cannot be
+// used with sqlserver.
+type Paginator[T any] struct {
+	query   string
+	columns []string
+	fields  [][]int
+	types   []reflect.Type
+	desc    bool
+	size    int
+}
+
+// NewPaginator[T] returns a Paginator which fetches size rows of query at a
+// time, sorted by columns, using the default mapper.
+func NewPaginator[T any](query string, size int, columns ...string) (*Paginator[T], error) {
+	return NewPaginatorMapper[T](reflectx.NewMapperFunc("db", sqlx.NameMapper), query, size, columns...)
+}
+
+// NewPaginatorMapper[T] returns a Paginator which maps the fields of T with m.
+func NewPaginatorMapper[T any](m *reflectx.Mapper, query string, size int, columns ...string) (*Paginator[T], error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("sqlx.db: expected a struct type for a Paginator, got %s", t)
+	}
+	if size < 1 {
+		return nil, fmt.Errorf("sqlx.db: invalid page size %d", size)
+	}
+	if len(columns) == 0 {
+		return nil, errors.New("sqlx.db: a Paginator needs at least one sort column")
+	}
+
+	p := &Paginator[T]{query: query, size: size}
+	tm := m.TypeMap(t)
+	for i, col := range columns {
+		name := strings.TrimSpace(col)
+		desc := false
+		if n := len(name) - len(" desc"); n > 0 && strings.EqualFold(name[n:], " desc") {
+			name, desc = strings.TrimSpace(name[:n]), true
+		}
+		if i == 0 {
+			p.desc = desc
+		} else if desc != p.desc {
+			return nil, errors.New("sqlx.db: the sort columns of a Paginator must all sort in the same direction")
+		}
+		fi, ok := tm.Names[name]
+		if !ok {
+			return nil, fmt.Errorf("sqlx.db: sort column %s is not mapped to a field of %s", name, t)
+		}
+		p.columns = append(p.columns, name)
+		p.fields = append(p.fields, fi.Index)
+		p.types = append(p.types, fi.Field.Type)
+	}
+	return p, nil
+}
+
+// pageQuery returns the query for a page after, or before if prev is set, the
+// row with keys.  Without keys, it is the query for the first or last page.
+func (p *Paginator[T]) pageQuery(prev bool, keys int) string {
+	// fetching the rows before a row sorts them the other way around
+	desc := p.desc != prev
+	op, order := " > ", ""
+	if desc {
+		op, order = " < ", " DESC"
+	}
+
+	var b strings.Builder
+	b.WriteString("SELECT * FROM (")
+	b.WriteString(p.query)
+	b.WriteString(") AS sqlx_page")
+	if keys > 0 {
+		b.WriteString(" WHERE ")
+		if len(p.columns) == 1 {
+			b.WriteString(p.columns[0] + op + "?")
+		} else {
+			b.WriteString("(" + strings.Join(p.columns, ", ") + ")" + op + "(")
+			b.WriteString(strings.TrimSuffix(strings.Repeat("?, ", keys), ", ") + ")")
+		}
+	}
+	b.WriteString(" ORDER BY ")
+	for i, c := range p.columns {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(c + order)
+	}
+	b.WriteString(" LIMIT " + strconv.Itoa(p.size+1))
+	return b.String()
+}
+
+// decode returns the direction and the sort key values of c.
+func (p *Paginator[T]) decode(c string) (bool, []any, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(c)
+	if err != nil {
+		return false, nil, ErrInvalidCursor
+	}
+	var cur cursor
+	if err = json.Unmarshal(raw, &cur); err != nil || len(cur.Keys) != len(p.columns) {
+		return false, nil, ErrInvalidCursor
+	}
+	// keys are decoded into values of their fields' types, so that eg. a
+	// time.Time is passed to the driver as one rather than as a string
+	keys := make([]any, len(cur.Keys))
+	for i, k := range cur.Keys {
+		v := reflect.New(p.types[i])
+		if err = json.Unmarshal(k, v.Interface()); err != nil {
+			return false, nil, ErrInvalidCursor
+		}
+		keys[i] = v.Elem().Interface()
+	}
+	return cur.Prev, keys, nil
+}
+
+// encode returns the cursor of the page after, or before if prev is set, v.
+func (p *Paginator[T]) encode(v *T, prev bool) (string, error) {
+	cur := cursor{Prev: prev, Keys: make([]json.RawMessage, len(p.fields))}
+	val := reflect.ValueOf(v).Elem()
+	for i, index := range p.fields {
+		k, err := json.Marshal(reflectx.FieldByIndexesReadOnly(val, index).Interface())
+		if err != nil {
+			return "", err
+		}
+		cur.Keys[i] = k
+	}
+	raw, err := json.Marshal(cur)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// Fetch returns the page of rows for cursor, which is either empty, for the
+// first page, or the Next or Prev cursor of a page fetched before.  The query
+// is run on q, rebound from the `?` bindvar, with args followed by the sort key
+// values of the cursor.
+func (p *Paginator[T]) Fetch(ctx context.Context, q sqlx.Queryable, cursor string, args ...any) (*Page[T], error) {
+	var prev bool
+	var keys []any
+	if cursor != "" {
+		var err error
+		if prev, keys, err = p.decode(cursor); err != nil {
+			return nil, err
+		}
+	}
+
+	page := &Page[T]{}
+	query := q.Rebind(p.pageQuery(prev, len(keys)))
+	if err := q.SelectContext(ctx, &page.Items, query, append(args[:len(args):len(args)], keys...)...); err != nil {
+		return nil, err
+	}
+	more := len(page.Items) > p.size
+	if more {
+		page.Items = page.Items[:p.size]
+	}
+	if prev {
+		for i, j := 0, len(page.Items)-1; i < j; i, j = i+1, j-1 {
+			page.Items[i], page.Items[j] = page.Items[j], page.Items[i]
+		}
+	}
+	if len(page.Items) == 0 {
+		return page, nil
+	}
+
+	// a page fetched going forward has a page before it if it was fetched
+	// after a cursor, and one after it if there were more rows;  and the
+	// other way around for a page fetched going back
+	hasNext, hasPrev := more, cursor != ""
+	if prev {
+		hasNext, hasPrev = true, more
+	}
+	var err error
+	if hasNext {
+		if page.Next, err = p.encode(&page.Items[len(page.Items)-1], false); err != nil {
+			return nil, err
+		}
+	}
+	if hasPrev {
+		if page.Prev, err = p.encode(&page.Items[0], true); err != nil {
+			return nil, err
+		}
+	}
+	return page, nil
+}
@@ -0,0 +1,99 @@
+package db
+
+import (
+	"context"
+	"testing"
+)
+
+func pageNames(page *Page[person]) []string {
+	names := make([]string, len(page.Items))
+	for i, p := range page.Items {
+		names[i] = p.Name
+	}
+	return names
+}
+
+func TestPaginator(t *testing.T) {
+	db := openTestDB(t)
+	db.MustExec(`INSERT INTO person (id, name) VALUES (5, 'bob'), (6, 'eve')`)
+	ctx := context.Background()
+
+	p, err := NewPaginator[person]("SELECT id, name FROM person WHERE id > ?", 2, "name", "id")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// ann, bob(2), bob(5), cid, dee, eve
+	expected := [][]string{{"ann", "bob"}, {"bob", "cid"}, {"dee", "eve"}}
+	var pages []*Page[person]
+	cursor := ""
+	for i := 0; ; i++ {
+		page, err := p.Fetch(ctx, db, cursor, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i >= len(expected) {
+			t.Fatalf("expected %d pages, got another: %v", len(expected), pageNames(page))
+		}
+		if got := pageNames(page); len(got) != 2 || got[0] != expected[i][0] || got[1] != expected[i][1] {
+			t.Errorf("page %d: expected %v, got %v", i, expected[i], got)
+		}
+		if (page.Prev == "") != (i == 0) {
+			t.Errorf("page %d: unexpected prev cursor %q", i, page.Prev)
+		}
+		pages = append(pages, page)
+		if page.Next == "" {
+			break
+		}
+		cursor = page.Next
+	}
+	if pages[1].Items[0].ID != 5 {
+		t.Errorf("expected the second bob on the second page, got %#v", pages[1].Items[0])
+	}
+
+	// and back again
+	for i := len(pages) - 1; i > 0; i-- {
+		page, err := p.Fetch(ctx, db, pages[i].Prev, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := pageNames(page), pageNames(pages[i-1]); len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+			t.Errorf("page %d: expected %v, got %v", i-1, want, got)
+		}
+		if page.Next != pages[i-1].Next || page.Prev != pages[i-1].Prev {
+			t.Errorf("page %d: expected cursors %q and %q, got %q and %q", i-1, pages[i-1].Prev, pages[i-1].Next, page.Prev, page.Next)
+		}
+	}
+
+	desc, err := NewPaginator[person]("SELECT id, name FROM person", 4, "id DESC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, err := desc.Fetch(ctx, db, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, err = desc.Fetch(ctx, db, page.Next)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) != 2 || page.Items[0].ID != 2 || page.Items[1].ID != 1 || page.Next != "" {
+		t.Errorf("unexpected last page %#v", page)
+	}
+
+	if _, err = p.Fetch(ctx, db, "bogus", 0); err != ErrInvalidCursor {
+		t.Errorf("expected ErrInvalidCursor, got %v", err)
+	}
+}
+
+func TestPaginatorErrors(t *testing.T) {
+	if _, err := NewPaginator[person]("SELECT * FROM person", 10, "age"); err == nil {
+		t.Error("expected an error for an unmapped sort column")
+	}
+	if _, err := NewPaginator[person]("SELECT * FROM person", 10, "name DESC", "id"); err == nil {
+		t.Error("expected an error for mixed sort directions")
+	}
+	if _, err := NewPaginator[person]("SELECT * FROM person", 0, "id"); err == nil {
+		t.Error("expected an error for an invalid page size")
+	}
+}
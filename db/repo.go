@@ -0,0 +1,245 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/bitbus/sqlx"
+	"github.com/bitbus/sqlx/reflectx"
+)
+
+// Tabler is implemented by types which name their own table for a Repo.
+type Tabler interface {
+	TableName() string
+}
+
+// repoColumn is a mapped field of a Repo's type.
+type repoColumn struct {
+	name  string
+	path  string
+	index []int
+	pk    bool
+	auto  bool
+}
+
+// Repo[T] runs the common queries on the table of the struct type T: Insert,
+// Update, Delete, FindByPK and FindAll.  The columns are the mapped fields of
+// T and of any structs embedded in it, and their tag options mark the primary
+// key and the columns which the database fills in:
+//
+//	type Person struct {
+//		ID   int64  `db:"id,pk,auto"`
+//		Name string `db:"name"`
+//	}
+//
+// Columns with the `pk` option make up the primary key, and those with `auto`
+// are left out of inserts and updates;  after an insert, they are read back
+// with RETURNING for postgres, or set to the LastInsertId otherwise.  The
+// table is the TableName of T if it is a Tabler, and its mapped type name
+// otherwise.  Queries are rebound for the Queryable they run on;  on an
+// sqlx.Cluster, Insert, Update and Delete run on the primary.
+type Repo[T any] struct {
+	table   string
+	columns []repoColumn
+	pk      []repoColumn
+}
+
+// NewRepo[T] returns a Repo for T, using the default mapper of an sqlx.DB.
+//
+// The Repo's columns are mapped once, here, and not with the Mapper of the
+// Queryable each query runs on, which still binds the named parameters and
+// scans the rows.  So if the DB's Mapper was changed, eg. with MapperFunc, the
+// two disagree on the names of the fields of T and the queries fail;  use
+// NewRepoMapper with the DB's Mapper instead.
+func NewRepo[T any]() (*Repo[T], error) {
+	return NewRepoMapper[T](reflectx.NewMapperFunc("db", sqlx.NameMapper))
+}
+
+// NewRepoMapper[T] returns a Repo for T which maps the fields of T with m,
+// which should be the Mapper of the DBs the Repo is used with.
+func NewRepoMapper[T any](m *reflectx.Mapper) (*Repo[T], error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("sqlx.db: expected a struct type for a Repo, got %s", t)
+	}
+
+	r := &Repo[T]{table: sqlx.NameMapper(t.Name())}
+	var zero T
+	if tn, ok := any(&zero).(Tabler); ok {
+		r.table = tn.TableName()
+	}
+
+	for _, fi := range m.TypeMap(t).FlatFields() {
+		_, pk := fi.Options["pk"]
+		_, auto := fi.Options["auto"]
+		col := repoColumn{name: fi.Name, path: fi.Path, index: fi.Index, pk: pk, auto: auto}
+		r.columns = append(r.columns, col)
+		if pk {
+			r.pk = append(r.pk, col)
+		}
+	}
+	if len(r.columns) == 0 {
+		return nil, fmt.Errorf("sqlx.db: no mapped fields in %s", t)
+	}
+	return r, nil
+}
+
+// Table returns the table of the Repo.
+func (r *Repo[T]) Table() string {
+	return r.table
+}
+
+func (r *Repo[T]) errNoPK() error {
+	return fmt.Errorf("sqlx.db: no primary key for table %s;  tag its columns with the pk option", r.table)
+}
+
+// where returns the condition which matches the primary key, using named
+// parameters if named is set, and the `?` bindvar otherwise.
+func (r *Repo[T]) where(named bool) string {
+	conds := make([]string, len(r.pk))
+	for i, c := range r.pk {
+		if named {
+			conds[i] = c.name + " = :" + c.path
+		} else {
+			conds[i] = c.name + " = ?"
+		}
+	}
+	return " WHERE " + strings.Join(conds, " AND ")
+}
+
+func (r *Repo[T]) selectQuery() string {
+	names := make([]string, len(r.columns))
+	for i, c := range r.columns {
+		names[i] = c.name
+	}
+	return "SELECT " + strings.Join(names, ", ") + " FROM " + r.table
+}
+
+// Insert inserts v, and sets its auto columns to the values the database
+// generated for them.  If all of the columns are auto, the row is inserted
+// with DEFAULT VALUES.
+func (r *Repo[T]) Insert(ctx context.Context, q sqlx.Queryable, v *T) error {
+	var names, params, returning []string
+	var auto []repoColumn
+	for _, c := range r.columns {
+		if c.auto {
+			auto = append(auto, c)
+			returning = append(returning, c.name)
+			continue
+		}
+		names = append(names, c.name)
+		params = append(params, ":"+c.path)
+	}
+	query := "INSERT INTO " + r.table + " (" + strings.Join(names, ", ") + ") VALUES (" + strings.Join(params, ", ") + ")"
+	if len(names) == 0 {
+		// MySQL is the one which does not support DEFAULT VALUES
+		switch q.DriverName() {
+		case "mysql", "nrmysql":
+			query = "INSERT INTO " + r.table + " () VALUES ()"
+		default:
+			query = "INSERT INTO " + r.table + " DEFAULT VALUES"
+		}
+	}
+
+	if len(auto) > 0 && sqlx.BindType(q.DriverName()) == sqlx.DOLLAR {
+		bound, args, err := q.BindNamed(query+" RETURNING "+strings.Join(returning, ", "), v)
+		if err != nil {
+			return err
+		}
+		val := reflect.ValueOf(v).Elem()
+		dest := make([]any, len(auto))
+		for i, c := range auto {
+			dest[i] = reflectx.FieldByIndexes(val, c.index).Addr().Interface()
+		}
+		// the RETURNING query is a write, which a Cluster would send to a replica
+		return q.QueryRowxContext(sqlx.WithPrimary(ctx), bound, args...).Scan(dest...)
+	}
+
+	res, err := q.NamedExecContext(ctx, query, v)
+	if err != nil || len(auto) != 1 {
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	f := reflectx.FieldByIndexes(reflect.ValueOf(v).Elem(), auto[0].index)
+	switch f.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f.SetInt(id)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		f.SetUint(uint64(id))
+	}
+	return nil
+}
+
+// Update updates the row with the primary key of v to the values of v,
+// returning the number of rows affected.
+func (r *Repo[T]) Update(ctx context.Context, q sqlx.Queryable, v *T) (int64, error) {
+	if len(r.pk) == 0 {
+		return 0, r.errNoPK()
+	}
+	var sets []string
+	for _, c := range r.columns {
+		if c.pk || c.auto {
+			continue
+		}
+		sets = append(sets, c.name+" = :"+c.path)
+	}
+	if len(sets) == 0 {
+		return 0, errors.New("sqlx.db: no columns to update in table " + r.table)
+	}
+	res, err := q.NamedExecContext(ctx, "UPDATE "+r.table+" SET "+strings.Join(sets, ", ")+r.where(true), v)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// Delete deletes the row with the primary key of v, returning the number of
+// rows affected.
+func (r *Repo[T]) Delete(ctx context.Context, q sqlx.Queryable, v *T) (int64, error) {
+	if len(r.pk) == 0 {
+		return 0, r.errNoPK()
+	}
+	res, err := q.NamedExecContext(ctx, "DELETE FROM "+r.table+r.where(true), v)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// FindByPK returns the row with the primary key pk, whose values are in the
+// order of the pk columns.  It returns sql.ErrNoRows if there is no such row.
+func (r *Repo[T]) FindByPK(ctx context.Context, q sqlx.Queryable, pk ...any) (*T, error) {
+	if len(r.pk) == 0 {
+		return nil, r.errNoPK()
+	}
+	if len(pk) != len(r.pk) {
+		return nil, fmt.Errorf("sqlx.db: expected %d primary key values for table %s, got %d", len(r.pk), r.table, len(pk))
+	}
+	dest := new(T)
+	if err := q.GetContext(ctx, dest, q.Rebind(r.selectQuery()+r.where(false)), pk...); err != nil {
+		return nil, err
+	}
+	return dest, nil
+}
+
+// FindAll returns all of the rows of the table, ordered by primary key if
+// there is one.
+func (r *Repo[T]) FindAll(ctx context.Context, q sqlx.Queryable) ([]T, error) {
+	query := r.selectQuery()
+	if len(r.pk) > 0 {
+		names := make([]string, len(r.pk))
+		for i, c := range r.pk {
+			names[i] = c.name
+		}
+		query += " ORDER BY " + strings.Join(names, ", ")
+	}
+	var dest []T
+	err := q.SelectContext(ctx, &dest, query)
+	return dest, err
+}
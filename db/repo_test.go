@@ -0,0 +1,188 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/bitbus/sqlx"
+	"github.com/mattn/go-sqlite3"
+)
+
+type audit struct {
+	CreatedBy string `db:"created_by"`
+}
+
+type account struct {
+	ID    int64  `db:"id,pk,auto"`
+	Name  string `db:"name"`
+	Email string `db:"email"`
+	audit
+}
+
+func (account) TableName() string { return "accounts" }
+
+func init() {
+	// sqlite accepts `$1` bindvars, so it can stand in for postgres
+	sql.Register("sqlite3_dollar", &sqlite3.SQLiteDriver{})
+	sqlx.BindDriver("sqlite3_dollar", sqlx.DOLLAR)
+}
+
+func TestRepo(t *testing.T) {
+	for _, driver := range []string{"sqlite3", "sqlite3_dollar"} {
+		t.Run(driver, func(t *testing.T) {
+			db, err := sqlx.Connect(driver, ":memory:")
+			if err != nil {
+				t.Skipf("sqlite3 unavailable: %v", err)
+			}
+			defer db.Close()
+			db.SetMaxOpenConns(1)
+			db.MustExec(`CREATE TABLE accounts (id integer primary key autoincrement, name text, email text, created_by text)`)
+			testRepo(t, db)
+		})
+	}
+}
+
+func testRepo(t *testing.T, db *sqlx.DB) {
+	ctx := context.Background()
+	repo, err := NewRepo[account]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repo.Table() != "accounts" {
+		t.Errorf("expected the TableName, got %q", repo.Table())
+	}
+
+	a := account{Name: "ann", Email: "ann@example.com", audit: audit{CreatedBy: "root"}}
+	if err = repo.Insert(ctx, db, &a); err != nil {
+		t.Fatal(err)
+	}
+	b := account{Name: "bob"}
+	if err = repo.Insert(ctx, db, &b); err != nil {
+		t.Fatal(err)
+	}
+	if a.ID != 1 || b.ID != 2 {
+		t.Errorf("expected the generated ids to be set, got %d and %d", a.ID, b.ID)
+	}
+
+	found, err := repo.FindByPK(ctx, db, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *found != a {
+		t.Errorf("expected %#v, got %#v", a, *found)
+	}
+	if _, err = repo.FindByPK(ctx, db, 42); err != sql.ErrNoRows {
+		t.Errorf("expected sql.ErrNoRows, got %v", err)
+	}
+
+	b.Email = "bob@example.com"
+	tx := db.MustBegin()
+	if n, err := repo.Update(ctx, tx, &b); err != nil || n != 1 {
+		t.Fatalf("expected 1 updated row, got %d, %v", n, err)
+	}
+	if n, err := repo.Delete(ctx, tx, &a); err != nil || n != 1 {
+		t.Fatalf("expected 1 deleted row, got %d, %v", n, err)
+	}
+	if err = tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	all, err := repo.FindAll(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 1 || all[0] != b {
+		t.Errorf("expected only %#v, got %#v", b, all)
+	}
+}
+
+type ticket struct {
+	ID int64 `db:"id,pk,auto"`
+}
+
+func TestRepoDefaultValues(t *testing.T) {
+	for _, driver := range []string{"sqlite3", "sqlite3_dollar"} {
+		t.Run(driver, func(t *testing.T) {
+			db, err := sqlx.Connect(driver, ":memory:")
+			if err != nil {
+				t.Skipf("sqlite3 unavailable: %v", err)
+			}
+			defer db.Close()
+			db.SetMaxOpenConns(1)
+			db.MustExec(`CREATE TABLE ticket (id integer primary key autoincrement)`)
+			repo, err := NewRepo[ticket]()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var a, b ticket
+			if err = repo.Insert(context.Background(), db, &a); err != nil {
+				t.Fatal(err)
+			}
+			if err = repo.Insert(context.Background(), db, &b); err != nil {
+				t.Fatal(err)
+			}
+			if a.ID != 1 || b.ID != 2 {
+				t.Errorf("expected the generated ids to be set, got %d and %d", a.ID, b.ID)
+			}
+		})
+	}
+}
+
+func TestRepoCluster(t *testing.T) {
+	open := func() *sqlx.DB {
+		db, err := sqlx.Connect("sqlite3_dollar", ":memory:")
+		if err != nil {
+			t.Skipf("sqlite3 unavailable: %v", err)
+		}
+		db.SetMaxOpenConns(1)
+		db.MustExec(`CREATE TABLE accounts (id integer primary key autoincrement, name text, email text, created_by text)`)
+		return db
+	}
+	c := sqlx.NewCluster(open(), open())
+	defer c.Close()
+	ctx := context.Background()
+	repo, err := NewRepo[account]()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := account{Name: "ann"}
+	if err = repo.Insert(ctx, c, &a); err != nil {
+		t.Fatal(err)
+	}
+	a.Email = "ann@example.com"
+	if _, err = repo.Update(ctx, c, &a); err != nil {
+		t.Fatal(err)
+	}
+	var primary, replica []account
+	c.Primary().MustExec(`INSERT INTO accounts (name, email, created_by) VALUES ('bob', '', '')`)
+	if err = c.Primary().Select(&primary, "SELECT * FROM accounts ORDER BY id"); err != nil {
+		t.Fatal(err)
+	}
+	if err = c.Replicas()[0].Select(&replica, "SELECT * FROM accounts"); err != nil {
+		t.Fatal(err)
+	}
+	if len(primary) != 2 || primary[0] != a || len(replica) != 0 {
+		t.Fatalf("expected the writes on the primary, got %v and %v", primary, replica)
+	}
+	if n, err := repo.Delete(ctx, c, &primary[1]); err != nil || n != 1 {
+		t.Errorf("expected 1 deleted row on the primary, got %d, %v", n, err)
+	}
+}
+
+func TestRepoErrors(t *testing.T) {
+	if _, err := NewRepo[int](); err == nil {
+		t.Error("expected an error for a type which is not a struct")
+	}
+	repo, err := NewRepo[person]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repo.Table() != "person" {
+		t.Errorf("expected the mapped type name, got %q", repo.Table())
+	}
+	if _, err = repo.FindByPK(context.Background(), openTestDB(t), 1); err == nil {
+		t.Error("expected an error without a primary key")
+	}
+}
@@ -0,0 +1,232 @@
+package sqlx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/bitbus/sqlx/reflectx"
+)
+
+// groupedChild is a slice field of a grouped scan's parent type, and the
+// columns which are scanned into its elements.
+type groupedChild struct {
+	index      []int
+	base       reflect.Type
+	isPtr      bool
+	columns    []int
+	traversals [][]int
+}
+
+// ScanGrouped scans all rows into dest, a pointer to a slice of structs, like
+// StructScan, but folds the rows of a one-to-many JOIN into one parent per
+// value of the key column, with the children of each parent appended to its
+// slice fields.  Columns named `<field>.<column>` are scanned into the
+// elements of the parent's slice field mapped to `<field>`, and the rest into
+// the parent itself:
+//
+//	type Author struct {
+//		ID    int    `db:"id"`
+//		Name  string `db:"name"`
+//		Posts []Post `db:"posts"`
+//	}
+//
+//	rows, err := db.Queryx(`SELECT a.id, a.name, p.id AS "posts.id", p.title AS "posts.title"
+//		FROM author a LEFT JOIN post p ON p.author_id = a.id ORDER BY a.id`)
+//	...
+//	var authors []Author
+//	err = sqlx.ScanGrouped(rows, &authors, "id")
+//
+// Parents are in the order of their first rows, and need not be sorted by key.
+// A row whose columns for a slice field are all NULL, as a LEFT JOIN gives for
+// a parent without children, adds no child to it.  Each row adds at most one
+// child to each slice field, so joining several one-to-many relations at once
+// repeats their children.  Only one level of children is grouped;  the fields
+// of the children are mapped as StructScan maps them.
+func ScanGrouped(rows rowsi, dest any, key string) error {
+	value := reflect.ValueOf(dest)
+	if value.Kind() != reflect.Ptr {
+		return errors.New("must pass a pointer, not a value, to ScanGrouped destination")
+	}
+	if value.IsNil() {
+		return errors.New("nil pointer passed to ScanGrouped destination")
+	}
+	direct := reflect.Indirect(value)
+
+	slice, err := baseType(value.Type(), reflect.Slice)
+	if err != nil {
+		return err
+	}
+	direct.SetLen(0)
+
+	isPtr := slice.Elem().Kind() == reflect.Ptr
+	base := reflectx.Deref(slice.Elem())
+	if base.Kind() != reflect.Struct || isScannable(base) {
+		return structOnlyError(base)
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	var m *reflectx.Mapper
+	switch rows := rows.(type) {
+	case *Rows:
+		m = rows.Mapper
+	default:
+		m = mapper()
+	}
+
+	tm := m.TypeMap(base)
+	keyField, ok := tm.Names[key]
+	if !ok {
+		return fmt.Errorf("missing key name %s in %T", key, dest)
+	}
+	// parents are found by their keys in a map, so a []byte key is converted
+	// to a string, and other keys must be comparable
+	keyType := keyField.Field.Type
+	bytesKey := keyType.Kind() == reflect.Slice && keyType.Elem().Kind() == reflect.Uint8
+	if !bytesKey && !keyType.Comparable() {
+		return fmt.Errorf("key name %s in %T has the incomparable type %s", key, dest, keyType)
+	}
+
+	// the traversals of the parent's columns;  those of the children's are
+	// left empty, so that fieldsByTraversal scans them into placeholders
+	fields := make([][]int, len(columns))
+	hasKey := false
+	var children []*groupedChild
+	byName := map[string]*groupedChild{}
+	for i, col := range columns {
+		if fi, ok := tm.Names[col]; ok {
+			fields[i] = fi.Index
+			hasKey = hasKey || fi == keyField
+			continue
+		}
+		if dot := strings.IndexByte(col, '.'); dot > 0 {
+			name := col[:dot]
+			c, ok := byName[name]
+			if !ok {
+				if fi := tm.Names[name]; fi != nil && fi.Field.Type.Kind() == reflect.Slice {
+					elem := fi.Field.Type.Elem()
+					if b := reflectx.Deref(elem); b.Kind() == reflect.Struct && !isScannable(b) {
+						c = &groupedChild{index: fi.Index, base: b, isPtr: elem.Kind() == reflect.Ptr}
+						children = append(children, c)
+					}
+				}
+				byName[name] = c
+			}
+			if c != nil {
+				if ctm := m.TypeMap(c.base); ctm.Names[col[dot+1:]] != nil {
+					c.columns = append(c.columns, i)
+					c.traversals = append(c.traversals, ctm.Names[col[dot+1:]].Index)
+					continue
+				}
+			}
+		}
+		if !isUnsafe(rows) {
+			return fmt.Errorf("missing destination name %s in %T", col, dest)
+		}
+	}
+
+	if !hasKey {
+		return fmt.Errorf("missing key column %s", key)
+	}
+
+	values := make([]any, len(columns))
+	parents := map[any]int{}
+	for rows.Next() {
+		vp := reflect.New(base)
+		v := vp.Elem()
+		if err = fieldsByTraversal(v, fields, values, true); err != nil {
+			return err
+		}
+		if err = rows.Scan(values...); err != nil {
+			return err
+		}
+
+		// scan the row again into the children whose columns are not all NULL
+		var found []reflect.Value
+		rescan := false
+		for _, c := range children {
+			null := true
+			for _, i := range c.columns {
+				if *values[i].(*any) != nil {
+					null = false
+					break
+				}
+			}
+			if null {
+				found = append(found, reflect.Value{})
+				continue
+			}
+			cp := reflect.New(c.base)
+			for j, i := range c.columns {
+				values[i] = reflectx.FieldByIndexes(cp.Elem(), c.traversals[j]).Addr().Interface()
+			}
+			found = append(found, cp)
+			rescan = true
+		}
+		if rescan {
+			if err = rows.Scan(values...); err != nil {
+				return err
+			}
+		}
+
+		var k any
+		if kv := reflectx.FieldByIndexesReadOnly(v, keyField.Index); bytesKey {
+			k = string(kv.Bytes())
+		} else {
+			k = kv.Interface()
+		}
+		if i, ok := parents[k]; ok {
+			v = reflect.Indirect(direct.Index(i))
+		} else {
+			parents[k] = direct.Len()
+			if isPtr {
+				direct.Set(reflect.Append(direct, vp))
+			} else {
+				direct.Set(reflect.Append(direct, v))
+				v = direct.Index(direct.Len() - 1)
+			}
+		}
+		for j, c := range children {
+			cp := found[j]
+			if !cp.IsValid() {
+				continue
+			}
+			f := reflectx.FieldByIndexes(v, c.index)
+			if c.isPtr {
+				f.Set(reflect.Append(f, cp))
+			} else {
+				f.Set(reflect.Append(f, cp.Elem()))
+			}
+		}
+	}
+	return rows.Err()
+}
+
+// SelectGrouped executes a query using the provided Queryer, and scans the
+// rows into dest with ScanGrouped, folding them by the key column.
+func SelectGrouped(q Queryer, dest any, key string, query string, args ...any) error {
+	rows, err := q.Queryx(query, args...)
+	if err != nil {
+		return err
+	}
+	// if something happens here, we want to make sure the rows are Closed
+	defer rows.Close()
+	return ScanGrouped(rows, dest, key)
+}
+
+// SelectGroupedContext executes a query using the provided QueryerContext, and
+// scans the rows into dest with ScanGrouped, folding them by the key column.
+func SelectGroupedContext(ctx context.Context, q QueryerContext, dest any, key string, query string, args ...any) error {
+	rows, err := q.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	// if something happens here, we want to make sure the rows are Closed
+	defer rows.Close()
+	return ScanGrouped(rows, dest, key)
+}
@@ -0,0 +1,105 @@
+package sqlx
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+var groupedSchema = Schema{
+	create: `
+CREATE TABLE author (
+	id integer,
+	name text
+);
+
+CREATE TABLE post (
+	id integer,
+	author_id integer,
+	title text
+);
+`,
+	drop: `
+drop table author;
+drop table post;
+`,
+}
+
+type groupedPost struct {
+	ID    int    `db:"id"`
+	Title string `db:"title"`
+}
+
+type groupedAuthor struct {
+	ID    int            `db:"id"`
+	Name  string         `db:"name"`
+	Posts []groupedPost  `db:"posts"`
+	Refs  []*groupedPost `db:"refs"`
+}
+
+func TestScanGrouped(t *testing.T) {
+	RunWithSchema(groupedSchema, t, func(db *DB, t *testing.T, now string) {
+		db.MustExec(`INSERT INTO author (id, name) VALUES (1, 'ann'), (2, 'bob'), (3, 'cid')`)
+		db.MustExec(`INSERT INTO post (id, author_id, title) VALUES (10, 1, 'a'), (11, 3, 'b'), (12, 1, 'c')`)
+
+		query := `SELECT a.id, a.name, p.id AS "posts.id", p.title AS "posts.title"
+			FROM author a LEFT JOIN post p ON p.author_id = a.id ORDER BY p.id, a.id`
+		var authors []groupedAuthor
+		if err := SelectGroupedContext(context.Background(), db, &authors, "id", query); err != nil {
+			t.Fatal(err)
+		}
+		// bob has no posts, so the LEFT JOIN sorts his NULL row first
+		if len(authors) != 3 || authors[0].Name != "bob" || authors[1].Name != "ann" || authors[2].Name != "cid" {
+			t.Fatalf("unexpected authors %#v", authors)
+		}
+		if len(authors[0].Posts) != 0 {
+			t.Errorf("expected no posts for bob, got %#v", authors[0].Posts)
+		}
+		if p := authors[1].Posts; len(p) != 2 || p[0].ID != 10 || p[1].Title != "c" {
+			t.Errorf("unexpected posts for ann: %#v", p)
+		}
+		if p := authors[2].Posts; len(p) != 1 || p[0].Title != "b" {
+			t.Errorf("unexpected posts for cid: %#v", p)
+		}
+
+		var ptrs []*groupedAuthor
+		query = `SELECT a.id, p.title AS "refs.title" FROM author a JOIN post p ON p.author_id = a.id ORDER BY p.id`
+		if err := SelectGrouped(db, &ptrs, "id", query); err != nil {
+			t.Fatal(err)
+		}
+		if len(ptrs) != 2 || len(ptrs[0].Refs) != 2 || ptrs[0].Refs[1].Title != "c" || ptrs[1].Refs[0].Title != "b" {
+			t.Errorf("unexpected authors %#v", ptrs)
+		}
+
+		err := SelectGrouped(db, &authors, "id", `SELECT a.id, p.title AS "posts.body" FROM author a JOIN post p ON p.author_id = a.id`)
+		if err == nil {
+			t.Error("expected an error for an unmapped child column")
+		}
+		err = SelectGrouped(db, &authors, "id", `SELECT name FROM author`)
+		if err == nil || !strings.Contains(err.Error(), "missing key column") {
+			t.Errorf("expected a missing key column error, got %v", err)
+		}
+
+		// a []byte key groups by its contents
+		var named []struct {
+			Name  []byte        `db:"name"`
+			Posts []groupedPost `db:"posts"`
+		}
+		query = `SELECT a.name, p.id AS "posts.id" FROM author a JOIN post p ON p.author_id = a.id ORDER BY p.id`
+		if err := SelectGrouped(db, &named, "name", query); err != nil {
+			t.Fatal(err)
+		}
+		if len(named) != 2 || string(named[0].Name) != "ann" || len(named[0].Posts) != 2 || len(named[1].Posts) != 1 {
+			t.Errorf("unexpected authors %#v", named)
+		}
+
+		var incomparable []struct {
+			IDs   []int         `db:"id"`
+			Posts []groupedPost `db:"posts"`
+		}
+		err = SelectGrouped(db, &incomparable, "id", `SELECT a.id FROM author a`)
+		if err == nil || !strings.Contains(err.Error(), "incomparable") {
+			t.Errorf("expected an incomparable key error, got %v", err)
+		}
+	})
+}
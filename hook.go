@@ -0,0 +1,275 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// QueryEvent describes a single query or exec sent to the database through a
+// DB, Tx, Conn or Stmt.
+type QueryEvent struct {
+	// Query is the query as it is sent to the driver, after named parameters
+	// have been bound and bindvars rebound.  For prepared statements it is the
+	// query the statement was prepared with.
+	Query string
+	// Args are the arguments the query is executed with.
+	Args []any
+	// Duration is how long the driver took to run the query.  For queries
+	// which return rows, it does not include iterating over the rows.
+	Duration time.Duration
+	// RowsAffected is the number of rows affected by an exec, or -1 if it is
+	// not known, as for queries which return rows.
+	RowsAffected int64
+	// Err is the error returned by the driver, if any.
+	Err error
+}
+
+// A Hook observes the queries run through a DB and everything derived from
+// it.  BeforeQuery is called before a query is sent to the driver, and may
+// change the event's Query and Args, or return a new context to be used for
+// the query and passed to AfterQuery.  Changes to the Query of a prepared
+// statement are ignored.  AfterQuery is called once the driver returns, with
+// the Duration, RowsAffected and Err of the event filled in.
+//
+// Hooks are called in the order they were added before a query, and in the
+// reverse order after it, so that each hook wraps the ones added after it.
+type Hook interface {
+	BeforeQuery(ctx context.Context, e *QueryEvent) context.Context
+	AfterQuery(ctx context.Context, e *QueryEvent)
+}
+
+// hooks is an interceptor chain of Hook which runs queries.  A nil hooks is
+// valid and runs queries without any overhead beyond the call.
+type hooks []Hook
+
+// add returns a new hooks with hs added;  it never modifies h in place, as
+// the slice may be shared by the Tx, Conn and Stmt created from a DB.
+func (h hooks) add(hs ...Hook) hooks {
+	r := make(hooks, 0, len(h)+len(hs))
+	r = append(r, h...)
+	return append(r, hs...)
+}
+
+func (h hooks) before(ctx context.Context, e *QueryEvent) context.Context {
+	for _, hook := range h {
+		ctx = hook.BeforeQuery(ctx, e)
+	}
+	return ctx
+}
+
+func (h hooks) after(ctx context.Context, e *QueryEvent) {
+	for i := len(h) - 1; i >= 0; i-- {
+		h[i].AfterQuery(ctx, e)
+	}
+}
+
+func (h hooks) exec(ctx context.Context, query string, args []any, fn func(context.Context, string, ...any) (sql.Result, error)) (sql.Result, error) {
+	if len(h) == 0 {
+		return fn(ctx, query, args...)
+	}
+	e := &QueryEvent{Query: query, Args: args, RowsAffected: -1}
+	ctx = h.before(ctx, e)
+	start := time.Now()
+	res, err := fn(ctx, e.Query, e.Args...)
+	e.Duration, e.Err = time.Since(start), err
+	if err == nil {
+		if n, err := res.RowsAffected(); err == nil {
+			e.RowsAffected = n
+		}
+	}
+	h.after(ctx, e)
+	return res, err
+}
+
+func (h hooks) query(ctx context.Context, query string, args []any, fn func(context.Context, string, ...any) (*sql.Rows, error)) (*sql.Rows, error) {
+	if len(h) == 0 {
+		return fn(ctx, query, args...)
+	}
+	e := &QueryEvent{Query: query, Args: args, RowsAffected: -1}
+	ctx = h.before(ctx, e)
+	start := time.Now()
+	rows, err := fn(ctx, e.Query, e.Args...)
+	e.Duration, e.Err = time.Since(start), err
+	h.after(ctx, e)
+	return rows, err
+}
+
+func (h hooks) queryRow(ctx context.Context, query string, args []any, fn func(context.Context, string, ...any) *sql.Row) *sql.Row {
+	if len(h) == 0 {
+		return fn(ctx, query, args...)
+	}
+	e := &QueryEvent{Query: query, Args: args, RowsAffected: -1}
+	ctx = h.before(ctx, e)
+	start := time.Now()
+	row := fn(ctx, e.Query, e.Args...)
+	e.Duration, e.Err = time.Since(start), row.Err()
+	h.after(ctx, e)
+	return row
+}
+
+// stmtFunc adapts a prepared statement's method, which takes no query, to
+// the function signature the hooks run.
+func stmtFunc[R any](fn func(context.Context, ...any) (R, error)) func(context.Context, string, ...any) (R, error) {
+	return func(ctx context.Context, _ string, args ...any) (R, error) {
+		return fn(ctx, args...)
+	}
+}
+
+// stmtRowFunc is stmtFunc for QueryRowContext.
+func stmtRowFunc(fn func(context.Context, ...any) *sql.Row) func(context.Context, string, ...any) *sql.Row {
+	return func(ctx context.Context, _ string, args ...any) *sql.Row {
+		return fn(ctx, args...)
+	}
+}
+
+// determine the hooks of any of our extensions
+func hooksFor(i any) hooks {
+	switch v := i.(type) {
+	case *DB:
+		return v.hooks
+	case *Tx:
+		return v.hooks
+	case *Conn:
+		return v.hooks
+	case *Stmt:
+		return v.hooks
+	default:
+		return nil
+	}
+}
+
+// AddHook adds hooks which observe every query run through this DB, and
+// through the Tx, Conn, Stmt and NamedStmt created from it after the call.
+// Add hooks while setting up the DB;  adding them while queries are running
+// is a data race.
+func (db *DB) AddHook(hs ...Hook) {
+	db.hooks = db.hooks.add(hs...)
+}
+
+// Exec executes a query without returning any rows, running the DB's hooks.
+// The args are for any placeholder parameters in the query.
+func (db *DB) Exec(query string, args ...any) (sql.Result, error) {
+	return db.ExecContext(context.Background(), query, args...)
+}
+
+// ExecContext executes a query without returning any rows, running the DB's
+// hooks.  The args are for any placeholder parameters in the query.
+func (db *DB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return db.hooks.exec(ctx, query, args, db.DB.ExecContext)
+}
+
+// Query executes a query that returns rows, running the DB's hooks.
+// The args are for any placeholder parameters in the query.
+func (db *DB) Query(query string, args ...any) (*sql.Rows, error) {
+	return db.QueryContext(context.Background(), query, args...)
+}
+
+// QueryContext executes a query that returns rows, running the DB's hooks.
+// The args are for any placeholder parameters in the query.
+func (db *DB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return db.hooks.query(ctx, query, args, db.DB.QueryContext)
+}
+
+// QueryRow executes a query that is expected to return at most one row,
+// running the DB's hooks.
+func (db *DB) QueryRow(query string, args ...any) *sql.Row {
+	return db.QueryRowContext(context.Background(), query, args...)
+}
+
+// QueryRowContext executes a query that is expected to return at most one row,
+// running the DB's hooks.
+func (db *DB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return db.hooks.queryRow(ctx, query, args, db.DB.QueryRowContext)
+}
+
+// Exec executes a query that doesn't return rows within a transaction,
+// running the hooks inherited from the DB.
+func (tx *Tx) Exec(query string, args ...any) (sql.Result, error) {
+	return tx.ExecContext(context.Background(), query, args...)
+}
+
+// ExecContext executes a query that doesn't return rows within a transaction,
+// running the hooks inherited from the DB.
+func (tx *Tx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return tx.hooks.exec(ctx, query, args, tx.Tx.ExecContext)
+}
+
+// Query executes a query that returns rows within a transaction, running the
+// hooks inherited from the DB.
+func (tx *Tx) Query(query string, args ...any) (*sql.Rows, error) {
+	return tx.QueryContext(context.Background(), query, args...)
+}
+
+// QueryContext executes a query that returns rows within a transaction,
+// running the hooks inherited from the DB.
+func (tx *Tx) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return tx.hooks.query(ctx, query, args, tx.Tx.QueryContext)
+}
+
+// QueryRow executes a query that is expected to return at most one row within
+// a transaction, running the hooks inherited from the DB.
+func (tx *Tx) QueryRow(query string, args ...any) *sql.Row {
+	return tx.QueryRowContext(context.Background(), query, args...)
+}
+
+// QueryRowContext executes a query that is expected to return at most one row
+// within a transaction, running the hooks inherited from the DB.
+func (tx *Tx) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return tx.hooks.queryRow(ctx, query, args, tx.Tx.QueryRowContext)
+}
+
+// ExecContext executes a query without returning any rows on this Conn,
+// running the hooks inherited from the DB.
+func (c *Conn) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return c.hooks.exec(ctx, query, args, c.Conn.ExecContext)
+}
+
+// QueryContext executes a query that returns rows on this Conn, running the
+// hooks inherited from the DB.
+func (c *Conn) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return c.hooks.query(ctx, query, args, c.Conn.QueryContext)
+}
+
+// QueryRowContext executes a query that is expected to return at most one row
+// on this Conn, running the hooks inherited from the DB.
+func (c *Conn) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return c.hooks.queryRow(ctx, query, args, c.Conn.QueryRowContext)
+}
+
+// Exec executes the prepared statement, running the hooks inherited from the
+// DB.  The args are for any placeholder parameters in the query.
+func (s *Stmt) Exec(args ...any) (sql.Result, error) {
+	return s.ExecContext(context.Background(), args...)
+}
+
+// ExecContext executes the prepared statement, running the hooks inherited
+// from the DB.  The args are for any placeholder parameters in the query.
+func (s *Stmt) ExecContext(ctx context.Context, args ...any) (sql.Result, error) {
+	return s.hooks.exec(ctx, s.query, args, stmtFunc(s.Stmt.ExecContext))
+}
+
+// Query executes the prepared query statement, running the hooks inherited
+// from the DB.  The args are for any placeholder parameters in the query.
+func (s *Stmt) Query(args ...any) (*sql.Rows, error) {
+	return s.QueryContext(context.Background(), args...)
+}
+
+// QueryContext executes the prepared query statement, running the hooks
+// inherited from the DB.  The args are for any placeholder parameters in the
+// query.
+func (s *Stmt) QueryContext(ctx context.Context, args ...any) (*sql.Rows, error) {
+	return s.hooks.query(ctx, s.query, args, stmtFunc(s.Stmt.QueryContext))
+}
+
+// QueryRow executes the prepared query statement, which is expected to return
+// at most one row, running the hooks inherited from the DB.
+func (s *Stmt) QueryRow(args ...any) *sql.Row {
+	return s.QueryRowContext(context.Background(), args...)
+}
+
+// QueryRowContext executes the prepared query statement, which is expected to
+// return at most one row, running the hooks inherited from the DB.
+func (s *Stmt) QueryRowContext(ctx context.Context, args ...any) *sql.Row {
+	return s.hooks.queryRow(ctx, s.query, args, stmtRowFunc(s.Stmt.QueryRowContext))
+}
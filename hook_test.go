@@ -0,0 +1,134 @@
+package sqlx
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+type ctxKey string
+
+type recordingHook struct {
+	events []QueryEvent
+	before int
+}
+
+func (h *recordingHook) BeforeQuery(ctx context.Context, e *QueryEvent) context.Context {
+	h.before++
+	return context.WithValue(ctx, ctxKey("hook"), h.before)
+}
+
+func (h *recordingHook) AfterQuery(ctx context.Context, e *QueryEvent) {
+	if ctx.Value(ctxKey("hook")) != h.before {
+		panic("AfterQuery did not receive the context returned by BeforeQuery")
+	}
+	h.events = append(h.events, *e)
+}
+
+func (h *recordingHook) last() QueryEvent {
+	return h.events[len(h.events)-1]
+}
+
+// rewriteHook upper cases every string argument of a query.
+type rewriteHook struct{}
+
+func (rewriteHook) BeforeQuery(ctx context.Context, e *QueryEvent) context.Context {
+	for i, a := range e.Args {
+		if s, ok := a.(string); ok {
+			e.Args[i] = strings.ToUpper(s)
+		}
+	}
+	return ctx
+}
+
+func (rewriteHook) AfterQuery(ctx context.Context, e *QueryEvent) {}
+
+func TestHooks(t *testing.T) {
+	RunWithSchema(defaultSchema, t, func(db *DB, t *testing.T, now string) {
+		loadDefaultFixture(db, t)
+		h := &recordingHook{}
+		db = db.Unsafe()
+		db.AddHook(h)
+
+		db.MustExec(db.Rebind("UPDATE person SET email = ? WHERE first_name = ?"), "jason@example.com", "Jason")
+		e := h.last()
+		if e.RowsAffected != 1 || e.Err != nil || len(e.Args) != 2 {
+			t.Errorf("unexpected exec event: %#v", e)
+		}
+		if !strings.HasPrefix(e.Query, "UPDATE person") {
+			t.Errorf("unexpected query %q", e.Query)
+		}
+
+		var people []Person
+		if err := db.Select(&people, "SELECT * FROM person"); err != nil {
+			t.Fatal(err)
+		}
+		if e = h.last(); e.RowsAffected != -1 || e.Query != "SELECT * FROM person" {
+			t.Errorf("unexpected query event: %#v", e)
+		}
+
+		if _, err := db.Exec("SELECT * FROM nonexistent"); err == nil {
+			t.Fatal("expected an error")
+		}
+		if h.last().Err == nil {
+			t.Error("expected the error to be passed to AfterQuery")
+		}
+
+		// the hooks are inherited by transactions and statements
+		n := len(h.events)
+		tx := db.MustBegin()
+		_, err := tx.NamedExec("INSERT INTO person (first_name, last_name, email) VALUES (:first_name, :last_name, :email)", Person{FirstName: "a", LastName: "b", Email: "c"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e = h.last(); e.Query != tx.Rebind("INSERT INTO person (first_name, last_name, email) VALUES (?, ?, ?)") {
+			t.Errorf("expected the rebound query, got %q", e.Query)
+		}
+		stmt, err := tx.Preparex(tx.Rebind("SELECT * FROM person WHERE first_name = ?"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var p Person
+		if err = stmt.Get(&p, "a"); err != nil {
+			t.Fatal(err)
+		}
+		if e = h.last(); e.Query != tx.Rebind("SELECT * FROM person WHERE first_name = ?") || e.Args[0] != "a" {
+			t.Errorf("unexpected statement event: %#v", e)
+		}
+		ns, err := tx.PrepareNamed("SELECT * FROM person WHERE first_name = :first_name")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = ns.Get(&p, p); err != nil {
+			t.Fatal(err)
+		}
+		tx.Rollback()
+		if len(h.events) != n+3 {
+			t.Errorf("expected 3 more events, got %d", len(h.events)-n)
+		}
+
+		conn, err := db.Connx(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		var one int
+		err = conn.GetContext(context.Background(), &one, "SELECT 1")
+		conn.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if h.last().Query != "SELECT 1" {
+			t.Errorf("expected the Conn query to be observed, got %q", h.last().Query)
+		}
+
+		// hooks can change the arguments of a query
+		db.AddHook(rewriteHook{})
+		var count int
+		if err = db.Get(&count, db.Rebind("SELECT count(*) FROM person WHERE first_name = ?"), "jason"); err != nil {
+			t.Fatal(err)
+		}
+		if h.last().Args[0] != "JASON" {
+			t.Errorf("expected the rewritten args to be observed, got %v", h.last().Args)
+		}
+	})
+}
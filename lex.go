@@ -0,0 +1,163 @@
+package sqlx
+
+import "strings"
+
+// SQL lexing
+//
+// Rebind, In and compileNamedQuery all look for bindvars in a query.  A `?`
+// or `:name` which appears inside of a string literal, a quoted identifier, a
+// comment or a postgres dollar-quoted string is not a bindvar, so all three
+// use literalEnd to step over those parts of the query.  Recognized are:
+//
+//   - 'single quoted' strings, with '' escapes, and E'...' strings which
+//     additionally allow backslash escapes;  for the mysql drivers, every
+//     single quoted string allows backslash escapes
+//   - "double quoted" and `backquoted` identifiers, with "" and `` escapes
+//   - -- line comments and /* block comments */
+//   - $$dollar quoted$$ and $tag$dollar quoted$tag$ strings
+//
+// Anything which is not terminated runs to the end of the query.
+
+// backslashEscapes returns whether the database of driverName escapes quotes
+// with a backslash in plain single quoted strings, as MySQL does by default.
+// Queries which are not bound for a driver are lexed as standard SQL.
+func backslashEscapes(driverName string) bool {
+	switch driverName {
+	case "mysql", "nrmysql":
+		return true
+	}
+	return false
+}
+
+// literalEnd returns the index just past the literal, quoted identifier or
+// comment which begins at q[i].  If none begins there, i is returned.  If
+// backslashes is set, a backslash escapes a quote in any single quoted string.
+func literalEnd[S ~string | ~[]byte](q S, i int, backslashes bool) int {
+	switch q[i] {
+	case '\'':
+		escapes := backslashes || i > 0 && (q[i-1] == 'E' || q[i-1] == 'e') && (i < 2 || !isIdentByte(q[i-2]))
+		return quotedEnd(q, i, '\'', escapes)
+	case '"', '`':
+		return quotedEnd(q, i, q[i], false)
+	case '-':
+		if i+1 < len(q) && q[i+1] == '-' {
+			for j := i + 2; j < len(q); j++ {
+				if q[j] == '\n' {
+					return j
+				}
+			}
+			return len(q)
+		}
+	case '/':
+		if i+1 < len(q) && q[i+1] == '*' {
+			for j := i + 2; j+1 < len(q); j++ {
+				if q[j] == '*' && q[j+1] == '/' {
+					return j + 2
+				}
+			}
+			return len(q)
+		}
+	case '$':
+		return dollarQuotedEnd(q, i)
+	}
+	return i
+}
+
+// quotedEnd returns the index just past the closing quote of the quoted
+// string or identifier beginning at q[i].  A doubled quote is an escaped
+// quote, and if backslashes is set so is a quote preceded by a backslash.
+func quotedEnd[S ~string | ~[]byte](q S, i int, quote byte, backslashes bool) int {
+	for j := i + 1; j < len(q); j++ {
+		switch {
+		case backslashes && q[j] == '\\':
+			j++
+		case q[j] == quote:
+			if j+1 < len(q) && q[j+1] == quote {
+				j++
+				continue
+			}
+			return j + 1
+		}
+	}
+	return len(q)
+}
+
+// dollarQuotedEnd returns the index just past a postgres dollar-quoted string
+// beginning at q[i], or i if q[i] does not open one.  Positional bindvars like
+// $1 are not dollar quotes, as tags cannot start with a digit.
+func dollarQuotedEnd[S ~string | ~[]byte](q S, i int) int {
+	// a $ within an identifier, eg. foo$bar, does not open a string
+	if i > 0 && isIdentByte(q[i-1]) {
+		return i
+	}
+	j := i + 1
+	if j < len(q) && q[j] != '$' {
+		if !isTagStartByte(q[j]) {
+			return i
+		}
+		for j < len(q) && q[j] != '$' {
+			if !isIdentByte(q[j]) {
+				return i
+			}
+			j++
+		}
+	}
+	if j >= len(q) {
+		return i
+	}
+
+	// the tag includes both dollar signs;  look for its next occurrence
+	tag := q[i : j+1]
+	for k := j + 1; k+len(tag) <= len(q); k++ {
+		if string(q[k:k+len(tag)]) == string(tag) {
+			return k + len(tag)
+		}
+	}
+	return len(q)
+}
+
+// nextBindVar returns the index of the next `?` bindvar in query at or after
+// from, skipping over literals and comments, or -1 if there is none.  If
+// backslashes is set, a backslash escapes a quote in any single quoted string.
+func nextBindVar(query string, from int, backslashes bool) int {
+	for i := from; i < len(query); i++ {
+		if query[i] == '?' {
+			return i
+		}
+		if end := literalEnd(query, i, backslashes); end > i {
+			i = end - 1
+		}
+	}
+	return -1
+}
+
+// inLists returns, for each `?` bindvar of query in order, whether it is the
+// whole list of an IN, as in `id IN (?)` or `id NOT IN ( ? )`.
+func inLists(query string, backslashes bool) []bool {
+	var lists []bool
+	for i := nextBindVar(query, 0, backslashes); i != -1; i = nextBindVar(query, i+1, backslashes) {
+		lists = append(lists, isInList(query, i))
+	}
+	return lists
+}
+
+// isInList returns whether the bindvar at query[i] is the whole list of an IN.
+func isInList(query string, i int) bool {
+	const space = " \t\r\n"
+	before := strings.TrimRight(query[:i], space)
+	after := strings.TrimLeft(query[i+1:], space)
+	if !strings.HasSuffix(before, "(") || !strings.HasPrefix(after, ")") {
+		return false
+	}
+	before = strings.TrimRight(before[:len(before)-1], space)
+	n := len(before)
+	return n >= 2 && strings.EqualFold(before[n-2:], "IN") && (n == 2 || !isIdentByte(before[n-3]))
+}
+
+func isTagStartByte(b byte) bool {
+	return b == '_' || b >= 0x80 || ('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z')
+}
+
+func isIdentByte(b byte) bool {
+	return isTagStartByte(b) || b == '$' || ('0' <= b && b <= '9')
+}
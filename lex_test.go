@@ -0,0 +1,172 @@
+package sqlx
+
+import (
+	"database/sql"
+	"testing"
+)
+
+func TestLiteralEnd(t *testing.T) {
+	tests := []struct {
+		q           string
+		i           int
+		want        int
+		backslashes bool
+	}{
+		{`'abc' x`, 0, 5, false},
+		{`'it''s' x`, 0, 7, false},
+		{`E'it\'s' x`, 1, 8, false},
+		{`'C:\' x`, 0, 5, false},
+		{`'it\'s' x`, 0, 7, true},
+		{`'C:\\' x`, 0, 6, true},
+		{`"a""b" x`, 0, 6, false},
+		{"`a``b` x", 0, 6, false},
+		{"-- ? :a\nx", 0, 7, false},
+		{"/* ? */ x", 0, 7, false},
+		{`$$ ? $$ x`, 0, 7, false},
+		{`$fn$ $$ ? $fn$ x`, 0, 14, false},
+		{`$1, $2`, 0, 0, false},
+		{`foo$bar$ x`, 3, 3, false},
+		{`'unterminated ?`, 0, 15, false},
+		{`x - y`, 2, 2, false},
+		{`x / y`, 2, 2, false},
+	}
+	for _, test := range tests {
+		if got := literalEnd(test.q, test.i, test.backslashes); got != test.want {
+			t.Errorf("literalEnd(%q, %d, %t): expected %d, got %d", test.q, test.i, test.backslashes, test.want, got)
+		}
+		if got := literalEnd([]byte(test.q), test.i, test.backslashes); got != test.want {
+			t.Errorf("literalEnd([]byte(%q), %d, %t): expected %d, got %d", test.q, test.i, test.backslashes, test.want, got)
+		}
+	}
+}
+
+func TestLexerRebind(t *testing.T) {
+	tests := []struct{ q, want string }{
+		{`SELECT '?', "?", ? -- ?` + "\n" + `, ?`, `SELECT '?', "?", $1 -- ?` + "\n" + `, $2`},
+		{`SELECT jsonb_path_query(doc, '$.a ? (@ > 1)') FROM t WHERE id = ?`, `SELECT jsonb_path_query(doc, '$.a ? (@ > 1)') FROM t WHERE id = $1`},
+		{`SELECT /* ? */ ?, $$ ? $$, ?`, `SELECT /* ? */ $1, $$ ? $$, $2`},
+	}
+	for _, test := range tests {
+		if got := Rebind(DOLLAR, test.q); got != test.want {
+			t.Errorf("\nexpected: `%s`\ngot:      `%s`", test.want, got)
+		}
+	}
+}
+
+func TestLexerIn(t *testing.T) {
+	q, args, err := In(`SELECT * FROM foo WHERE note = 'why?' AND id IN (?) AND x = ?`, []int{1, 2, 3}, "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `SELECT * FROM foo WHERE note = 'why?' AND id IN (?, ?, ?) AND x = ?`
+	if q != want {
+		t.Errorf("\nexpected: `%s`\ngot:      `%s`", want, q)
+	}
+	if len(args) != 4 {
+		t.Errorf("expected 4 args, got %d", len(args))
+	}
+}
+
+func TestLexerInDialects(t *testing.T) {
+	// mysql escapes quotes with backslashes in plain strings
+	mysql := NewDb(&sql.DB{}, "mysql")
+	q, args, err := mysql.In(`SELECT * FROM t WHERE name = 'O\'Brien' AND id IN (?)`, []int{1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `SELECT * FROM t WHERE name = 'O\'Brien' AND id IN (?, ?)`
+	if q != want || len(args) != 2 {
+		t.Errorf("\nexpected: `%s`\ngot:      `%s` %v", want, q, args)
+	}
+
+	// while standard SQL, as sqlite and postgres use, does not, outside of
+	// postgres E'...' strings
+	query := `SELECT * FROM t WHERE path = 'C:\' AND id IN (?) AND note = E'it\'s ?'`
+	want = `SELECT * FROM t WHERE path = 'C:\' AND id IN (?, ?) AND note = E'it\'s ?'`
+	sqlite := NewDb(&sql.DB{}, "sqlite3")
+	for _, in := range []func(string, ...any) (string, []any, error){In, sqlite.In} {
+		q, args, err := in(query, []int{1, 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if q != want || len(args) != 2 {
+			t.Errorf("\nexpected: `%s`\ngot:      `%s` %v", want, q, args)
+		}
+	}
+	pg := NewDb(&sql.DB{}, "postgres")
+	if q, _, err = pg.In(query, []int{1, 2}); err != nil {
+		t.Fatal(err)
+	}
+	if q != Rebind(DOLLAR, want) {
+		t.Errorf("\nexpected: `%s`\ngot:      `%s`", Rebind(DOLLAR, want), q)
+	}
+}
+
+func TestLexerTrailingBackslash(t *testing.T) {
+	db, err := Connect("sqlite3", ":memory:")
+	if err != nil {
+		t.Skipf("sqlite3 unavailable: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	db.MustExec(`CREATE TABLE t (id integer, path text)`)
+	db.MustExec(`INSERT INTO t (id, path) VALUES (1, 'a'), (2, 'b'), (3, 'c')`)
+
+	q, args, err := db.In(`SELECT count(*) FROM t WHERE path <> 'C:\' AND id IN (?)`, []int{1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var n int
+	if err = db.Get(&n, q, args...); err != nil || n != 2 {
+		t.Errorf("expected 2 rows, got %d, %v", n, err)
+	}
+	if _, err = db.NamedExec(`UPDATE t SET path = 'C:\' WHERE id = :id`, map[string]any{"id": 3}); err != nil {
+		t.Fatal(err)
+	}
+	if err = db.Get(&n, `SELECT count(*) FROM t WHERE path = 'C:\'`); err != nil || n != 1 {
+		t.Errorf("expected 1 updated row, got %d, %v", n, err)
+	}
+}
+
+func TestLexerCompileNamedQuery(t *testing.T) {
+	tests := []struct {
+		q, want string
+		names   []string
+	}{
+		{
+			q:     `SELECT ':a' || "b:c", :d -- :e` + "\n" + `FROM x WHERE y = :f`,
+			want:  `SELECT ':a' || "b:c", $1 -- :e` + "\n" + `FROM x WHERE y = $2`,
+			names: []string{"d", "f"},
+		},
+		{
+			q:     `CREATE FUNCTION f() RETURNS int AS $$ SELECT x::int FROM t WHERE y = :y $$ LANGUAGE sql; SELECT :z`,
+			want:  `CREATE FUNCTION f() RETURNS int AS $$ SELECT x::int FROM t WHERE y = :y $$ LANGUAGE sql; SELECT $1`,
+			names: []string{"z"},
+		},
+		{
+			q:     `SELECT :a/* :b */, E'\':c', :d`,
+			want:  `SELECT $1/* :b */, E'\':c', $2`,
+			names: []string{"a", "d"},
+		},
+	}
+	for _, test := range tests {
+		q, names, err := compileNamedQuery([]byte(test.q), DOLLAR)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if q != test.want {
+			t.Errorf("\nexpected: `%s`\ngot:      `%s`", test.want, q)
+		}
+		if len(names) != len(test.names) {
+			t.Errorf("expected names %v, got %v", test.names, names)
+			continue
+		}
+		for i := range names {
+			if names[i] != test.names[i] {
+				t.Errorf("expected names %v, got %v", test.names, names)
+				break
+			}
+		}
+	}
+}
@@ -13,12 +13,14 @@ package sqlx
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"reflect"
 	"regexp"
 	"strconv"
+	"strings"
 	"unicode"
 
 	"github.com/bitbus/sqlx/reflectx"
@@ -26,10 +28,21 @@ import (
 
 // NamedStmt is a prepared statement that executes named queries.  Prepare it
 // how you would execute a NamedQuery, but pass in a struct or map when executing.
+//
+// As with NamedQuery, a slice whose parameter is the whole list of an IN, as
+// in `id IN (:ids)`, is expanded into a list of bindvars.  As this changes the
+// query, the statement is then not used;  the expanded query is run unprepared
+// on the DB or Tx the statement was prepared on instead.
 type NamedStmt struct {
 	Params      []string
 	QueryString string
 	Stmt        *Stmt
+
+	// unbound is the query using the `?` bindvar, and ext what the statement
+	// was prepared on;  slices which are the list of an IN are expanded into
+	// it and run as an ad hoc query, as they change the number of bindvars.
+	unbound string
+	ext     ExtContext
 }
 
 // Close closes the named statement.
@@ -40,21 +53,13 @@ func (n *NamedStmt) Close() error {
 // Exec executes a named statement using the struct passed.
 // Any named placeholder parameters are replaced with fields from arg.
 func (n *NamedStmt) Exec(arg any) (sql.Result, error) {
-	args, err := bindAnyArgs(n.Params, arg, n.Stmt.Mapper)
-	if err != nil {
-		return *new(sql.Result), err
-	}
-	return n.Stmt.Exec(args...)
+	return n.ExecContext(context.Background(), arg)
 }
 
 // Query executes a named statement using the struct argument, returning rows.
 // Any named placeholder parameters are replaced with fields from arg.
 func (n *NamedStmt) Query(arg any) (*sql.Rows, error) {
-	args, err := bindAnyArgs(n.Params, arg, n.Stmt.Mapper)
-	if err != nil {
-		return nil, err
-	}
-	return n.Stmt.Query(args...)
+	return n.QueryContext(context.Background(), arg)
 }
 
 // QueryRow executes a named statement against the database.  Because sqlx cannot
@@ -62,11 +67,7 @@ func (n *NamedStmt) Query(arg any) (*sql.Rows, error) {
 // returns a *sqlx.Row instead.
 // Any named placeholder parameters are replaced with fields from arg.
 func (n *NamedStmt) QueryRow(arg any) *Row {
-	args, err := bindAnyArgs(n.Params, arg, n.Stmt.Mapper)
-	if err != nil {
-		return &Row{err: err}
-	}
-	return n.Stmt.QueryRowx(args...)
+	return n.QueryRowContext(context.Background(), arg)
 }
 
 // MustExec execs a NamedStmt, panicing on error
@@ -117,7 +118,7 @@ func (n *NamedStmt) Get(dest any, arg any) error {
 
 // Unsafe creates an unsafe version of the NamedStmt
 func (n *NamedStmt) Unsafe() *NamedStmt {
-	r := &NamedStmt{Params: n.Params, Stmt: n.Stmt, QueryString: n.QueryString}
+	r := &NamedStmt{Params: n.Params, Stmt: n.Stmt, QueryString: n.QueryString, unbound: n.unbound, ext: n.ext}
 	r.Stmt.unsafe = true
 	return r
 }
@@ -131,7 +132,7 @@ type namedPreparer interface {
 
 func prepareNamed(p namedPreparer, query string) (*NamedStmt, error) {
 	bindType := BindType(p.DriverName())
-	q, args, err := compileNamedQuery([]byte(query), bindType)
+	q, args, err := compileNamed([]byte(query), bindType, backslashEscapes(p.DriverName()))
 	if err != nil {
 		return nil, err
 	}
@@ -139,11 +140,45 @@ func prepareNamed(p namedPreparer, query string) (*NamedStmt, error) {
 	if err != nil {
 		return nil, err
 	}
+	return newNamedStmt(p, p.DriverName(), query, q, args, stmt), nil
+}
+
+// newNamedStmt returns a NamedStmt for query, which is compiled to q for
+// driverName with the names params, and prepared as stmt on p.
+func newNamedStmt(p any, driverName, query, q string, params []string, stmt *Stmt) *NamedStmt {
+	unbound := q
+	if BindType(driverName) != QUESTION {
+		unbound, _, _ = compileNamed([]byte(query), QUESTION, backslashEscapes(driverName))
+	}
+	ext, _ := p.(ExtContext)
 	return &NamedStmt{
 		QueryString: q,
-		Params:      args,
+		Params:      params,
 		Stmt:        stmt,
-	}, nil
+		unbound:     unbound,
+		ext:         ext,
+	}
+}
+
+// expand returns the ad hoc query and arguments to run instead of the prepared
+// statement if any of args is a slice which is the list of an IN, or ok == false
+// if there are none.
+func (n *NamedStmt) expand(args []any) (query string, expanded []any, ok bool, err error) {
+	if !hasInSlice(args) {
+		return "", nil, false, nil
+	}
+	var driverName string
+	if n.ext != nil {
+		driverName = n.ext.DriverName()
+	}
+	if !hasInList(n.unbound, args, backslashEscapes(driverName)) {
+		return "", nil, false, nil
+	}
+	if n.ext == nil {
+		return "", nil, true, errors.New("sqlx: cannot expand slice arguments of a NamedStmt which was not prepared on a DB or Tx")
+	}
+	query, expanded, err = bindIn(BindType(driverName), backslashEscapes(driverName), n.unbound, args)
+	return query, expanded, true, err
 }
 
 // convertMapStringInterface attempts to convert v to map[string]any.
@@ -210,8 +245,8 @@ func bindMapArgs(names []string, arg map[string]any) ([]any, error) {
 // bindStruct binds a named parameter query with fields from a struct argument.
 // The rules for binding field names to parameter names follow the same
 // conventions as for StructScan, including obeying the `db` struct tags.
-func bindStruct(bindType int, query string, arg any, m *reflectx.Mapper) (string, []any, error) {
-	bound, names, err := compileNamedQuery([]byte(query), bindType)
+func bindStruct(bindType int, backslashes bool, query string, arg any, m *reflectx.Mapper) (string, []any, error) {
+	bound, names, err := compileNamed([]byte(query), bindType, backslashes)
 	if err != nil {
 		return "", []any{}, err
 	}
@@ -221,7 +256,60 @@ func bindStruct(bindType int, query string, arg any, m *reflectx.Mapper) (string
 		return "", []any{}, err
 	}
 
-	return bound, arglist, nil
+	return expandNamed(bindType, backslashes, query, bound, arglist)
+}
+
+// hasInSlice returns whether any of args is a slice which In expands.
+func hasInSlice(args []any) bool {
+	for _, arg := range args {
+		if _, ok := asSliceForIn(arg); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// hasInList returns whether any of args is a slice whose bindvar in query,
+// which uses the `?` bindvar, is the whole list of an IN.
+func hasInList(query string, args []any, backslashes bool) bool {
+	lists := inLists(query, backslashes)
+	for i, arg := range args {
+		if _, ok := asSliceForIn(arg); ok && i < len(lists) && lists[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// bindIn expands the slices in args which are the lists of an IN into lists
+// of bindvars in query, which uses the `?` bindvar, and rebinds it to bindType.
+func bindIn(bindType int, backslashes bool, query string, args []any) (string, []any, error) {
+	query, args, err := in(query, args, backslashes, true)
+	if err != nil {
+		return "", []any{}, err
+	}
+	return Rebind(bindType, query), args, nil
+}
+
+// expandNamed expands the slice arguments of the named query, which was bound
+// to bindType as bound, which are the whole list of an IN, so that a named
+// query like `WHERE id IN (:ids)` works with a slice of ids.  Other slices,
+// eg. for `id = ANY(:ids)` in postgres, are passed on as they are.
+func expandNamed(bindType int, backslashes bool, query, bound string, arglist []any) (string, []any, error) {
+	if !hasInSlice(arglist) {
+		return bound, arglist, nil
+	}
+	unbound := bound
+	if bindType != QUESTION {
+		var err error
+		if unbound, _, err = compileNamed([]byte(query), QUESTION, backslashes); err != nil {
+			return "", []any{}, err
+		}
+	}
+	if !hasInList(unbound, arglist, backslashes) {
+		return bound, arglist, nil
+	}
+	return bindIn(bindType, backslashes, unbound, arglist)
 }
 
 var valuesReg = regexp.MustCompile(`\)\s*(?i)VALUES\s*\(`)
@@ -270,10 +358,10 @@ func fixBound(bound string, loop int) string {
 
 // bindArray binds a named parameter query with fields from an array or slice of
 // structs argument.
-func bindArray(bindType int, query string, arg any, m *reflectx.Mapper) (string, []any, error) {
+func bindArray(bindType int, backslashes bool, query string, arg any, m *reflectx.Mapper) (string, []any, error) {
 	// do the initial binding with QUESTION;  if bindType is not question,
 	// we can rebind it at the end.
-	bound, names, err := compileNamedQuery([]byte(query), QUESTION)
+	bound, names, err := compileNamed([]byte(query), QUESTION, backslashes)
 	if err != nil {
 		return "", []any{}, err
 	}
@@ -301,14 +389,17 @@ func bindArray(bindType int, query string, arg any, m *reflectx.Mapper) (string,
 }
 
 // bindMap binds a named parameter query with a map of arguments.
-func bindMap(bindType int, query string, args map[string]any) (string, []any, error) {
-	bound, names, err := compileNamedQuery([]byte(query), bindType)
+func bindMap(bindType int, backslashes bool, query string, args map[string]any) (string, []any, error) {
+	bound, names, err := compileNamed([]byte(query), bindType, backslashes)
 	if err != nil {
 		return "", []any{}, err
 	}
 
 	arglist, err := bindMapArgs(names, args)
-	return bound, arglist, err
+	if err != nil {
+		return "", []any{}, err
+	}
+	return expandNamed(bindType, backslashes, query, bound, arglist)
 }
 
 // -- Compilation of Named Queries
@@ -327,8 +418,16 @@ var allowedBindRunes = []*unicode.RangeTable{unicode.Letter, unicode.Digit}
 // up for the slightly slower ad-hoc NamedExec/NamedQuery.
 
 // compile a NamedQuery into an unbound query (using the '?' bindvar) and
-// a list of names.
+// a list of names.  Names within string literals, quoted identifiers, comments
+// and dollar-quoted strings are not bound.  For backwards compatibility, the
+// '::' escape sequence is still unescaped within quoted strings and identifiers.
 func compileNamedQuery(qs []byte, bindType int) (query string, names []string, err error) {
+	return compileNamed(qs, bindType, false)
+}
+
+// compileNamed is compileNamedQuery, where a backslash escapes a quote in any
+// single quoted string of qs if backslashes is set.
+func compileNamed(qs []byte, bindType int, backslashes bool) (query string, names []string, err error) {
 	names = make([]string, 0, 10)
 	rebound := make([]byte, 0, len(qs))
 
@@ -337,7 +436,8 @@ func compileNamedQuery(qs []byte, bindType int) (query string, names []string, e
 	currentVar := 1
 	name := make([]byte, 0, 10)
 
-	for i, b := range qs {
+	for i := 0; i < len(qs); i++ {
+		b := qs[i]
 		// a ':' while we're in a name is an error
 		if b == ':' {
 			// if this is the second ':' in a '::' escape sequence, append a ':'
@@ -351,6 +451,7 @@ func compileNamedQuery(qs []byte, bindType int) (query string, names []string, e
 			}
 			inName = true
 			name = []byte{}
+			continue
 		} else if inName && i > 0 && b == '=' && len(name) == 0 {
 			rebound = append(rebound, ':', '=')
 			inName = false
@@ -359,12 +460,14 @@ func compileNamedQuery(qs []byte, bindType int) (query string, names []string, e
 		} else if inName && (unicode.IsOneOf(allowedBindRunes, rune(b)) || b == '_' || b == '.') && i != last {
 			// append the byte to the name if we are in a name and not on the last byte
 			name = append(name, b)
+			continue
 			// if we're in a name and it's not an allowed character, the name is done
 		} else if inName {
 			inName = false
 			// if this is the final byte of the string and it is part of the name, then
 			// make sure to add it to the name
-			if i == last && unicode.IsOneOf(allowedBindRunes, rune(b)) {
+			partOfName := i == last && unicode.IsOneOf(allowedBindRunes, rune(b))
+			if partOfName {
 				name = append(name, b)
 			}
 			// add the string representation to the names list
@@ -390,35 +493,82 @@ func compileNamedQuery(qs []byte, bindType int) (query string, names []string, e
 				}
 				currentVar++
 			}
-			// add this byte to string unless it was not part of the name
-			if i != last {
-				rebound = append(rebound, b)
-			} else if !unicode.IsOneOf(allowedBindRunes, rune(b)) {
-				rebound = append(rebound, b)
+			// the byte which ended the name is handled like any other below
+			if partOfName {
+				continue
+			}
+		}
+
+		// skip over literals and comments, copying them to the rebound query
+		if end := literalEnd(qs, i, backslashes); end > i {
+			if b == '\'' || b == '"' || b == '`' {
+				rebound = appendUnescapedColons(rebound, qs[i:end])
+			} else {
+				rebound = append(rebound, qs[i:end]...)
 			}
-		} else {
-			// this is a normal byte and should just go onto the rebound query
-			rebound = append(rebound, b)
+			i = end - 1
+			continue
 		}
+		// this is a normal byte and should just go onto the rebound query
+		rebound = append(rebound, b)
 	}
 
 	return string(rebound), names, err
 }
 
+// appendUnescapedColons appends lit to dst, replacing each '::' with ':'.
+func appendUnescapedColons(dst, lit []byte) []byte {
+	for i := 0; i < len(lit); i++ {
+		dst = append(dst, lit[i])
+		if lit[i] == ':' && i+1 < len(lit) && lit[i+1] == ':' {
+			i++
+		}
+	}
+	return dst
+}
+
+// EscapeColons escapes the colons of query, eg. those of postgres `::` casts,
+// so that a named query leaves them as they are.  Each colon is doubled,
+// except within comments and dollar-quoted strings, which named queries copy
+// verbatim.  It is for queries which are combined with named parameters after
+// they are written, as a query builder does.
+func EscapeColons(query string) string {
+	var b strings.Builder
+	b.Grow(len(query))
+	for i := 0; i < len(query); i++ {
+		if end := literalEnd(query, i, false); end > i {
+			if c := query[i]; c == '\'' || c == '"' || c == '`' {
+				b.WriteString(strings.ReplaceAll(query[i:end], ":", "::"))
+			} else {
+				b.WriteString(query[i:end])
+			}
+			i = end - 1
+			continue
+		}
+		if query[i] == ':' {
+			b.WriteByte(':')
+		}
+		b.WriteByte(query[i])
+	}
+	return b.String()
+}
+
 // BindNamed binds a struct or a map to a query with named parameters.
 // DEPRECATED: use sqlx.Named` instead of this, it may be removed in future.
 func BindNamed(bindType int, query string, arg any) (string, []any, error) {
-	return bindNamedMapper(bindType, query, arg, mapper())
+	return bindNamedMapper(bindType, false, query, arg, mapper())
 }
 
 // Named takes a query using named parameters and an argument and
 // returns a new query with a list of args that can be executed by
 // a database.  The return value uses the `?` bindvar.
 func Named(query string, arg any) (string, []any, error) {
-	return bindNamedMapper(QUESTION, query, arg, mapper())
+	return bindNamedMapper(QUESTION, false, query, arg, mapper())
 }
 
-func bindNamedMapper(bindType int, query string, arg any, m *reflectx.Mapper) (string, []any, error) {
+// bindNamedMapper binds the named query for bindType, with the backslash
+// escapes of MySQL strings if backslashes is set.
+func bindNamedMapper(bindType int, backslashes bool, query string, arg any, m *reflectx.Mapper) (string, []any, error) {
 	t := reflect.TypeOf(arg)
 	k := t.Kind()
 	switch {
@@ -427,19 +577,21 @@ func bindNamedMapper(bindType int, query string, arg any, m *reflectx.Mapper) (s
 		if !ok {
 			return "", nil, fmt.Errorf("sqlx.bindNamedMapper: unsupported map type: %T", arg)
 		}
-		return bindMap(bindType, query, m)
+		return bindMap(bindType, backslashes, query, m)
 	case k == reflect.Array || k == reflect.Slice:
-		return bindArray(bindType, query, arg, m)
+		return bindArray(bindType, backslashes, query, arg, m)
 	default:
-		return bindStruct(bindType, query, arg, m)
+		return bindStruct(bindType, backslashes, query, arg, m)
 	}
 }
 
 // NamedQuery binds a named query and then runs Query on the result using the
 // provided Ext (sqlx.Tx, sqlx.Db).  It works with both structs and with
-// map[string]any types.
+// map[string]any types.  A slice whose parameter is the whole list of an IN,
+// as in `id IN (:ids)`, is expanded into a list of bindvars like In does;
+// other slices, eg. for `id = ANY(:ids)`, are passed to the driver as they are.
 func NamedQuery(e Ext, query string, arg any) (*Rows, error) {
-	q, args, err := bindNamedMapper(BindType(e.DriverName()), query, arg, mapperFor(e))
+	q, args, err := bindNamedMapper(BindType(e.DriverName()), backslashEscapes(e.DriverName()), query, arg, mapperFor(e))
 	if err != nil {
 		return nil, err
 	}
@@ -450,7 +602,7 @@ func NamedQuery(e Ext, query string, arg any) (*Rows, error) {
 // then runs Exec on the result.  Returns an error from the binding
 // or the query execution itself.
 func NamedExec(e Ext, query string, arg any) (sql.Result, error) {
-	q, args, err := bindNamedMapper(BindType(e.DriverName()), query, arg, mapperFor(e))
+	q, args, err := bindNamedMapper(BindType(e.DriverName()), backslashEscapes(e.DriverName()), query, arg, mapperFor(e))
 	if err != nil {
 		return nil, err
 	}
@@ -14,7 +14,7 @@ type namedPreparerContext interface {
 
 func prepareNamedContext(ctx context.Context, p namedPreparerContext, query string) (*NamedStmt, error) {
 	bindType := BindType(p.DriverName())
-	q, args, err := compileNamedQuery([]byte(query), bindType)
+	q, args, err := compileNamed([]byte(query), bindType, backslashEscapes(p.DriverName()))
 	if err != nil {
 		return nil, err
 	}
@@ -22,11 +22,7 @@ func prepareNamedContext(ctx context.Context, p namedPreparerContext, query stri
 	if err != nil {
 		return nil, err
 	}
-	return &NamedStmt{
-		QueryString: q,
-		Params:      args,
-		Stmt:        stmt,
-	}, nil
+	return newNamedStmt(p, p.DriverName(), query, q, args, stmt), nil
 }
 
 // ExecContext executes a named statement using the struct passed.
@@ -36,6 +32,12 @@ func (n *NamedStmt) ExecContext(ctx context.Context, arg any) (sql.Result, error
 	if err != nil {
 		return *new(sql.Result), err
 	}
+	if q, args, ok, err := n.expand(args); ok {
+		if err != nil {
+			return *new(sql.Result), err
+		}
+		return n.ext.ExecContext(ctx, q, args...)
+	}
 	return n.Stmt.ExecContext(ctx, args...)
 }
 
@@ -46,6 +48,12 @@ func (n *NamedStmt) QueryContext(ctx context.Context, arg any) (*sql.Rows, error
 	if err != nil {
 		return nil, err
 	}
+	if q, args, ok, err := n.expand(args); ok {
+		if err != nil {
+			return nil, err
+		}
+		return n.ext.QueryContext(ctx, q, args...)
+	}
 	return n.Stmt.QueryContext(ctx, args...)
 }
 
@@ -58,6 +66,13 @@ func (n *NamedStmt) QueryRowContext(ctx context.Context, arg any) *Row {
 	if err != nil {
 		return &Row{err: err}
 	}
+	if q, args, ok, err := n.expand(args); ok {
+		if err != nil {
+			return &Row{err: err}
+		}
+		rows, err := n.ext.QueryContext(ctx, q, args...)
+		return &Row{rows: rows, err: err, unsafe: n.Stmt.unsafe, Mapper: n.Stmt.Mapper}
+	}
 	return n.Stmt.QueryRowxContext(ctx, args...)
 }
 
@@ -111,7 +126,7 @@ func (n *NamedStmt) GetContext(ctx context.Context, dest any, arg any) error {
 // provided Ext (sqlx.Tx, sqlx.Db).  It works with both structs and with
 // map[string]any types.
 func NamedQueryContext(ctx context.Context, e ExtContext, query string, arg any) (*Rows, error) {
-	q, args, err := bindNamedMapper(BindType(e.DriverName()), query, arg, mapperFor(e))
+	q, args, err := bindNamedMapper(BindType(e.DriverName()), backslashEscapes(e.DriverName()), query, arg, mapperFor(e))
 	if err != nil {
 		return nil, err
 	}
@@ -122,7 +137,7 @@ func NamedQueryContext(ctx context.Context, e ExtContext, query string, arg any)
 // then runs Exec on the result.  Returns an error from the binding
 // or the query execution itself.
 func NamedExecContext(ctx context.Context, e ExtContext, query string, arg any) (sql.Result, error) {
-	q, args, err := bindNamedMapper(BindType(e.DriverName()), query, arg, mapperFor(e))
+	q, args, err := bindNamedMapper(BindType(e.DriverName()), backslashEscapes(e.DriverName()), query, arg, mapperFor(e))
 	if err != nil {
 		return nil, err
 	}
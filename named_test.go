@@ -103,6 +103,20 @@ type Test struct {
 	t *testing.T
 }
 
+func TestEscapeColons(t *testing.T) {
+	q := `SELECT x::int, 'a:b::c', "d:e", $$ :: $$, y := 1 -- ::` + "\n" + `/* :f */`
+	escaped := EscapeColons(q)
+	if want := `SELECT x::::int, 'a::b::::c', "d::e", $$ :: $$, y ::= 1 -- ::` + "\n" + `/* :f */`; escaped != want {
+		t.Errorf("\nexpected: `%s`\ngot:      `%s`", want, escaped)
+	}
+	bound, names, err := compileNamedQuery([]byte(escaped+" AND z = :z"), DOLLAR)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bound != q+" AND z = $1" || len(names) != 1 || names[0] != "z" {
+		t.Errorf("expected the escaped query to compile back, got `%s` %v", bound, names)
+	}
+}
 func (t Test) Error(err error, msg ...any) {
 	t.t.Helper()
 	if err != nil {
@@ -122,7 +136,6 @@ func (t Test) Errorf(err error, format string, args ...any) {
 }
 
 func TestEscapedColons(t *testing.T) {
-	t.Skip("not sure it is possible to support this in general case without an SQL parser")
 	var qs = `SELECT * FROM testtable WHERE timeposted BETWEEN (now() AT TIME ZONE 'utc') AND
 	(now() AT TIME ZONE 'utc') - interval '01:30:00') AND name = '\'this is a test\'' and id = :id`
 	_, _, err := compileNamedQuery([]byte(qs), DOLLAR)
@@ -433,3 +446,93 @@ func TestFixBounds(t *testing.T) {
 		})
 	}
 }
+
+func TestNamedIn(t *testing.T) {
+	type filter struct {
+		Names []string `db:"names"`
+		Email string   `db:"email"`
+	}
+	q, args, err := Named("SELECT * FROM person WHERE first_name IN (:names) AND email <> :email", filter{Names: []string{"Jason", "John"}, Email: "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q != "SELECT * FROM person WHERE first_name IN (?, ?) AND email <> ?" || len(args) != 3 {
+		t.Errorf("unexpected expansion %q %v", q, args)
+	}
+	q, args, err = bindNamedMapper(DOLLAR, false, "SELECT :a WHERE x not in ( :ids ) AND y = :b", map[string]any{"a": 1, "ids": []int{2, 3}, "b": []byte("b")}, mapper())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q != "SELECT $1 WHERE x not in ( $2, $3 ) AND y = $4" || len(args) != 4 {
+		t.Errorf("unexpected expansion %q %v", q, args)
+	}
+	if _, _, err = Named("SELECT 1 WHERE x IN (:names)", filter{}); err == nil {
+		t.Error("expected an error for an empty slice")
+	}
+
+	// slices which are not the list of an IN are passed on as they are
+	for _, query := range []string{
+		"SELECT :a WHERE x = ANY(:ids) AND y IN (:b)",
+		"SELECT :a WHERE x IN (:ids, 4) AND y = :b",
+		"SELECT :a WHERE x = ':ids IN (?)' AND join_in(:ids) AND y = :b",
+	} {
+		q, args, err = bindNamedMapper(DOLLAR, false, query, map[string]any{"a": 1, "ids": []int{2, 3}, "b": []byte("b")}, mapper())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want, _, _ := compileNamedQuery([]byte(query), DOLLAR); q != want || len(args) != 3 {
+			t.Errorf("unexpected expansion %q %v", q, args)
+		}
+	}
+	// and do not keep a NamedStmt from running its prepared statement
+	ns := &NamedStmt{unbound: "SELECT ? WHERE x = ANY(?)"}
+	if _, _, ok, _ := ns.expand([]any{1, []int{2, 3}}); ok {
+		t.Error("expected a slice which is not the list of an IN not to be expanded")
+	}
+
+	RunWithSchema(defaultSchema, t, func(db *DB, t *testing.T, now string) {
+		loadDefaultFixture(db, t)
+		f := filter{Names: []string{"Jason", "John", "Jane"}, Email: "jmoiron@jmoiron.net"}
+		query := "SELECT * FROM person WHERE first_name IN (:names) AND email <> :email ORDER BY first_name"
+
+		rows, err := db.NamedQuery(query, f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var n int
+		for rows.Next() {
+			n++
+		}
+		rows.Close()
+		if n != 1 {
+			t.Errorf("expected 1 person from NamedQuery, got %d", n)
+		}
+
+		check := func(ns *NamedStmt) {
+			var people []Person
+			if err := ns.Select(&people, f); err != nil {
+				t.Fatal(err)
+			}
+			if len(people) != 1 || people[0].FirstName != "John" {
+				t.Errorf("unexpected people %v", people)
+			}
+			var p Person
+			if err := ns.Get(&p, filter{Names: []string{"John", "Jason"}}); err != nil {
+				t.Fatal(err)
+			}
+			if p.FirstName != "Jason" {
+				t.Errorf("expected Jason, got %q", p.FirstName)
+			}
+		}
+		ns, err := db.PrepareNamed(query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		check(ns)
+
+		tx := db.MustBegin()
+		defer tx.Rollback()
+		check(tx.NamedStmt(ns))
+		ns.Close()
+	})
+}
@@ -0,0 +1,172 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"strings"
+
+	"github.com/bitbus/sqlx/reflectx"
+)
+
+// QualifiedSeparator separates the alias from the column name in the columns
+// of a qualified Rows, eg. `a__id`.
+const QualifiedSeparator = "__"
+
+// SetQualified turns qualified column mapping on or off for the struct scans
+// of r, which tells apart the columns of a query like
+//
+//	SELECT a.id, a.name, b.id, b.name FROM foos AS a JOIN foos AS b ON a.parent = b.id
+//
+// which share names, for a destination such as
+//
+//	type Pair struct {
+//		Child  Foo `db:"child,alias=a"`
+//		Parent Foo `db:"parent,alias=b"`
+//	}
+//
+// A column named `<alias>__<column>`, eg. `a__id` for `a.id AS a__id`, maps to
+// the column of the struct field with that alias option or mapped name, eg.
+// `child.id`.  Any other column maps to the field of the same name if there is
+// one, as usual;  otherwise its occurrences map, in order, to the fields of
+// that name in the struct fields which have an alias option, in the order they
+// are declared, so that the first `id` above maps to `child.id` and the second
+// to `parent.id`.
+//
+// It must be set before the first row is scanned.
+func (r *Rows) SetQualified(on bool) {
+	r.qualified = on
+	r.started = false
+}
+
+func isQualified(rows rowsi) bool {
+	r, ok := rows.(*Rows)
+	return ok && r.qualified
+}
+
+// qualifiedTraversals returns the traversals of t for columns, mapped as
+// described by SetQualified.  Columns which do not map have empty traversals.
+func qualifiedTraversals(m *reflectx.Mapper, t reflect.Type, columns []string) [][]int {
+	tm := m.TypeMap(reflectx.Deref(t))
+
+	aliases := map[string]*reflectx.FieldInfo{}
+	var candidates []*reflectx.FieldInfo
+	for _, fi := range tm.Index {
+		if alias, ok := fi.Options["alias"]; ok && alias != "" {
+			aliases[alias] = fi
+		}
+	}
+	for _, fi := range tm.Index {
+		for p := fi.Parent; p != nil && p != tm.Tree; p = p.Parent {
+			if _, ok := p.Options["alias"]; ok {
+				candidates = append(candidates, fi)
+				break
+			}
+		}
+	}
+	reflectx.SortByIndex(candidates)
+
+	seen := map[string]int{}
+	r := make([][]int, len(columns))
+	for i, col := range columns {
+		if alias, name, ok := strings.Cut(col, QualifiedSeparator); ok && alias != "" {
+			parent := aliases[alias]
+			if parent == nil {
+				parent = tm.Names[alias]
+			}
+			if parent != nil {
+				name = strings.ReplaceAll(name, QualifiedSeparator, ".")
+				if fi := tm.Names[parent.Path+"."+name]; fi != nil {
+					r[i] = fi.Index
+				}
+				continue
+			}
+		}
+		if fi := tm.Names[col]; fi != nil {
+			r[i] = fi.Index
+			continue
+		}
+		n := seen[col]
+		for _, fi := range candidates {
+			if fi.Name != col {
+				continue
+			}
+			if n == 0 {
+				r[i] = fi.Index
+				break
+			}
+			n--
+		}
+		seen[col]++
+	}
+	return r
+}
+
+func queryQualified(rows *Rows, err error) (*Rows, error) {
+	if err != nil {
+		return nil, err
+	}
+	rows.SetQualified(true)
+	return rows, nil
+}
+
+func getQualified(rows *Rows, dest any) error {
+	defer rows.Close()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return sql.ErrNoRows
+	}
+	return rows.StructScan(dest)
+}
+
+// SelectQualified executes a query using the provided Queryer, and scans each
+// row into dest, as Select does, with qualified column mapping as described by
+// Rows.SetQualified.
+func SelectQualified(q Queryer, dest any, query string, args ...any) error {
+	rows, err := queryQualified(q.Queryx(query, args...))
+	if err != nil {
+		return err
+	}
+	// if something happens here, we want to make sure the rows are Closed
+	defer rows.Close()
+	return scanAll(rows, dest, true)
+}
+
+// SelectQualifiedContext executes a query using the provided QueryerContext,
+// and scans each row into dest, as SelectContext does, with qualified column
+// mapping as described by Rows.SetQualified.
+func SelectQualifiedContext(ctx context.Context, q QueryerContext, dest any, query string, args ...any) error {
+	rows, err := queryQualified(q.QueryxContext(ctx, query, args...))
+	if err != nil {
+		return err
+	}
+	// if something happens here, we want to make sure the rows are Closed
+	defer rows.Close()
+	return scanAll(rows, dest, true)
+}
+
+// GetQualified does a query using the provided Queryer, and scans the first
+// row into the struct dest, as Get does, with qualified column mapping as
+// described by Rows.SetQualified.  It returns sql.ErrNoRows if there are no
+// rows.
+func GetQualified(q Queryer, dest any, query string, args ...any) error {
+	rows, err := queryQualified(q.Queryx(query, args...))
+	if err != nil {
+		return err
+	}
+	return getQualified(rows, dest)
+}
+
+// GetQualifiedContext does a query using the provided QueryerContext, and
+// scans the first row into the struct dest, as GetContext does, with qualified
+// column mapping as described by Rows.SetQualified.  It returns sql.ErrNoRows
+// if there are no rows.
+func GetQualifiedContext(ctx context.Context, q QueryerContext, dest any, query string, args ...any) error {
+	rows, err := queryQualified(q.QueryxContext(ctx, query, args...))
+	if err != nil {
+		return err
+	}
+	return getQualified(rows, dest)
+}
@@ -0,0 +1,80 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+)
+
+type qualifiedFoo struct {
+	ID   int    `db:"id"`
+	Name string `db:"name"`
+}
+
+type qualifiedPair struct {
+	Child  qualifiedFoo `db:"child,alias=a"`
+	Parent qualifiedFoo `db:"parent,alias=b"`
+}
+
+func TestQualified(t *testing.T) {
+	var schema = Schema{
+		create: `
+CREATE TABLE foos (
+	id integer,
+	name text,
+	parent integer
+);
+`,
+		drop: `drop table foos;`,
+	}
+	RunWithSchema(schema, t, func(db *DB, t *testing.T, now string) {
+		db.MustExec(`INSERT INTO foos (id, name, parent) VALUES (1, 'root', 0), (2, 'leaf', 1), (3, 'twig', 1)`)
+		ctx := context.Background()
+
+		var pairs []qualifiedPair
+		query := `SELECT a.id, a.name, b.id, b.name FROM foos AS a JOIN foos AS b ON a.parent = b.id ORDER BY a.id`
+		if err := SelectQualified(db, &pairs, query); err != nil {
+			t.Fatal(err)
+		}
+		expected := []qualifiedPair{{qualifiedFoo{2, "leaf"}, qualifiedFoo{1, "root"}}, {qualifiedFoo{3, "twig"}, qualifiedFoo{1, "root"}}}
+		if len(pairs) != 2 || pairs[0] != expected[0] || pairs[1] != expected[1] {
+			t.Errorf("expected %v, got %v", expected, pairs)
+		}
+
+		// prefixed columns may come in any order, and be named by alias or field
+		var pair qualifiedPair
+		query = `SELECT b.name AS b__name, a.id AS child__id, b.id AS b__id, a.name AS a__name FROM foos AS a JOIN foos AS b ON a.parent = b.id WHERE a.id = ?`
+		if err := GetQualifiedContext(ctx, db, &pair, db.Rebind(query), 3); err != nil {
+			t.Fatal(err)
+		}
+		if pair != expected[1] {
+			t.Errorf("expected %v, got %v", expected[1], pair)
+		}
+		if err := GetQualified(db, &pair, db.Rebind(query), 42); err != sql.ErrNoRows {
+			t.Errorf("expected sql.ErrNoRows, got %v", err)
+		}
+
+		rows, err := db.QueryxContext(ctx, `SELECT a.id, b.id FROM foos AS a JOIN foos AS b ON a.parent = b.id`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows.SetQualified(true)
+		for rows.Next() {
+			if err = rows.StructScan(&pair); err != nil {
+				t.Fatal(err)
+			}
+			if pair.Parent.ID != 1 || pair.Child.ID < 2 {
+				t.Errorf("unexpected pair %v", pair)
+			}
+		}
+		rows.Close()
+
+		// without qualified mapping, the columns are ambiguous
+		if err = db.Select(&pairs, `SELECT a.id, b.id FROM foos AS a JOIN foos AS b ON a.parent = b.id`); err == nil {
+			t.Error("expected an error for unqualified columns")
+		}
+		if err = SelectQualifiedContext(ctx, db, &pairs, `SELECT a.id AS c__id FROM foos AS a`); err == nil {
+			t.Error("expected an error for an unknown alias")
+		}
+	})
+}
@@ -9,6 +9,7 @@ package reflectx
 import (
 	"reflect"
 	"runtime"
+	"sort"
 	"strings"
 	"sync"
 )
@@ -57,6 +58,46 @@ func (f StructMap) GetByTraversal(index []int) *FieldInfo {
 	return tree
 }
 
+// FlatFields returns the fields of the struct and of the structs embedded in
+// it, in the order of their declaration, with the fields of an embedded struct
+// in place of it.  The fields of other nested structs are left out, as are
+// fields hidden by another with the same path.
+func (f StructMap) FlatFields() []*FieldInfo {
+	// the index is in breadth first order
+	index := make([]*FieldInfo, len(f.Index))
+	copy(index, f.Index)
+	SortByIndex(index)
+
+	var fields []*FieldInfo
+FieldLoop:
+	for _, fi := range index {
+		if fi.Embedded || f.Names[fi.Path] != fi {
+			continue
+		}
+		for p := fi.Parent; p != nil && p != f.Tree; p = p.Parent {
+			if !p.Embedded {
+				continue FieldLoop
+			}
+		}
+		fields = append(fields, fi)
+	}
+	return fields
+}
+
+// SortByIndex sorts fields by their Index, which is the depth first order of
+// their declaration.
+func SortByIndex(fields []*FieldInfo) {
+	sort.SliceStable(fields, func(i, j int) bool {
+		a, b := fields[i].Index, fields[j].Index
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+}
+
 // Mapper is a general purpose mapper of names to struct fields.  A Mapper
 // behaves like most marshallers in the standard library, obeying a field tag
 // for name mapping but also providing a basic transform function.
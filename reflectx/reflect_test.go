@@ -627,6 +627,34 @@ func TestGetByTraversal(t *testing.T) {
 	}
 }
 
+func TestFlatFields(t *testing.T) {
+	type Base struct {
+		ID   int
+		Name string
+	}
+	type Place struct {
+		City string
+	}
+	type Person struct {
+		First string
+		Base
+		Name  string
+		Place Place
+		Last  string
+	}
+
+	m := NewMapperFunc("db", func(n string) string { return n })
+	var names []string
+	for _, fi := range m.TypeMap(reflect.TypeOf(Person{})).FlatFields() {
+		names = append(names, fi.Path)
+	}
+	// Base.Name is hidden by Name, and the fields of Place are not flattened
+	expected := []string{"First", "ID", "Name", "Place", "Last"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected %v, got %v", expected, names)
+	}
+}
+
 // TestMapperMethodsByName tests Mapper methods FieldByName and TraversalsByName
 func TestMapperMethodsByName(t *testing.T) {
 	type C struct {
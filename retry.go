@@ -0,0 +1,105 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy is how WithTxRetry and WithTxxRetry retry a transaction which
+// failed with a transient error, such as a serialization failure or deadlock.
+// The classifiers in the retry package recognize these errors for the
+// postgres, mysql and sqlite3 drivers.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times the transaction is run.  If
+	// it is less than 1, it is run once.
+	MaxAttempts int
+	// Backoff returns how long to wait before the given retry, starting at 1.
+	// If it is nil, retries are not delayed.
+	Backoff func(retry int) time.Duration
+	// Retryable reports whether a transaction which failed with err can be
+	// retried.  If it is nil, no error is retried.
+	Retryable func(err error) bool
+}
+
+// ExponentialBackoff returns a RetryPolicy Backoff which doubles the delay
+// with each retry, starting at base and capped at max.  The delays are
+// jittered to between half and all of their value, so that transactions
+// which conflicted with each other do not retry at the same time.
+func ExponentialBackoff(base, max time.Duration) func(int) time.Duration {
+	return func(retry int) time.Duration {
+		d := base
+		for i := 1; i < retry && d < max; i++ {
+			d *= 2
+		}
+		if d > max {
+			d = max
+		}
+		if d <= 1 {
+			return d
+		}
+		return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+	}
+}
+
+// run calls fn until it succeeds, returns an error which is not retryable, or
+// the attempts run out.  Waiting between attempts is aborted, returning the
+// last error, if ctx is done.
+func (p RetryPolicy) run(ctx context.Context, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= p.MaxAttempts || p.Retryable == nil || !p.Retryable(err) {
+			return err
+		}
+		if p.Backoff == nil {
+			if ctx.Err() != nil {
+				return err
+			}
+			continue
+		}
+		t := time.NewTimer(p.Backoff(attempt))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return err
+		case <-t.C:
+		}
+	}
+}
+
+// WithTxRetry is like WithTx, but retries the transaction according to policy
+// if it fails with a retryable error, either from handle or from the commit.
+// The handle must be safe to run more than once.
+func (db *DB) WithTxRetry(ctx context.Context, opts *sql.TxOptions, policy RetryPolicy, handle func(tx *sql.Tx) error) error {
+	return policy.run(ctx, func() error {
+		return db.WithTx(ctx, opts, handle)
+	})
+}
+
+// WithTxxRetry is like WithTxx, but retries the transaction according to
+// policy if it fails with a retryable error, either from handle or from the
+// commit.  The handle must be safe to run more than once.
+func (db *DB) WithTxxRetry(ctx context.Context, opts *sql.TxOptions, policy RetryPolicy, handle func(tx *Tx) error) error {
+	return policy.run(ctx, func() error {
+		return db.WithTxx(ctx, opts, handle)
+	})
+}
+
+// WithTxRetry is like WithTx, but retries the transaction according to policy
+// if it fails with a retryable error, either from handle or from the commit.
+// The handle must be safe to run more than once.
+func (c *Conn) WithTxRetry(ctx context.Context, opts *sql.TxOptions, policy RetryPolicy, handle func(tx *sql.Tx) error) error {
+	return policy.run(ctx, func() error {
+		return c.WithTx(ctx, opts, handle)
+	})
+}
+
+// WithTxxRetry is like WithTxx, but retries the transaction according to
+// policy if it fails with a retryable error, either from handle or from the
+// commit.  The handle must be safe to run more than once.
+func (c *Conn) WithTxxRetry(ctx context.Context, opts *sql.TxOptions, policy RetryPolicy, handle func(tx *Tx) error) error {
+	return policy.run(ctx, func() error {
+		return c.WithTxx(ctx, opts, handle)
+	})
+}
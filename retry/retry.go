@@ -0,0 +1,51 @@
+// Package retry classifies the transient errors of the postgres, mysql and
+// sqlite3 drivers, after which a transaction can be retried, for use as the
+// Retryable of a sqlx.RetryPolicy:
+//
+//	policy := sqlx.RetryPolicy{
+//		MaxAttempts: 5,
+//		Backoff:     sqlx.ExponentialBackoff(10*time.Millisecond, time.Second),
+//		Retryable:   retry.Postgres,
+//	}
+//	err := db.WithTxxRetry(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable}, policy, handle)
+package retry
+
+import (
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+)
+
+// Postgres reports whether err is a postgres serialization failure (40001) or
+// a detected deadlock (40P01).
+func Postgres(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	switch pqErr.Code {
+	case "40001", "40P01":
+		return true
+	}
+	return false
+}
+
+// MySQL reports whether err is a mysql deadlock (1213) or lock wait timeout
+// (1205).
+func MySQL(err error) bool {
+	var myErr *mysql.MySQLError
+	if !errors.As(err, &myErr) {
+		return false
+	}
+	switch myErr.Number {
+	case 1213, 1205:
+		return true
+	}
+	return false
+}
+
+// Any reports whether err is retryable for any of the drivers.
+func Any(err error) bool {
+	return Postgres(err) || MySQL(err) || SQLite(err)
+}
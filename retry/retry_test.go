@@ -0,0 +1,37 @@
+package retry
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+)
+
+func TestClassifiers(t *testing.T) {
+	var tests = []struct {
+		err             error
+		postgres, mySQL bool
+	}{
+		{&pq.Error{Code: "40001"}, true, false},
+		{fmt.Errorf("commit: %w", &pq.Error{Code: "40P01"}), true, false},
+		{&pq.Error{Code: "23505"}, false, false},
+		{&mysql.MySQLError{Number: 1213}, false, true},
+		{&mysql.MySQLError{Number: 1205}, false, true},
+		{&mysql.MySQLError{Number: 1062}, false, false},
+		{errors.New("40001"), false, false},
+		{nil, false, false},
+	}
+	for _, test := range tests {
+		if Postgres(test.err) != test.postgres {
+			t.Errorf("Postgres(%v): expected %v", test.err, test.postgres)
+		}
+		if MySQL(test.err) != test.mySQL {
+			t.Errorf("MySQL(%v): expected %v", test.err, test.mySQL)
+		}
+		if Any(test.err) != (test.postgres || test.mySQL) {
+			t.Errorf("Any(%v): expected %v", test.err, test.postgres || test.mySQL)
+		}
+	}
+}
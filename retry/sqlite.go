@@ -0,0 +1,19 @@
+//go:build cgo
+
+package retry
+
+import (
+	"errors"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// SQLite reports whether err is SQLITE_BUSY, which is returned when the
+// database is locked by another connection.
+func SQLite(err error) bool {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	return sqliteErr.Code == sqlite3.ErrBusy
+}
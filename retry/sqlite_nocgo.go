@@ -0,0 +1,9 @@
+//go:build !cgo
+
+package retry
+
+// SQLite reports whether err is SQLITE_BUSY.  The sqlite3 driver requires cgo,
+// so without it there are no sqlite errors and SQLite always returns false.
+func SQLite(err error) bool {
+	return false
+}
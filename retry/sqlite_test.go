@@ -0,0 +1,18 @@
+//go:build cgo
+
+package retry
+
+import (
+	"testing"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+func TestSQLite(t *testing.T) {
+	if !SQLite(sqlite3.Error{Code: sqlite3.ErrBusy}) {
+		t.Error("expected SQLITE_BUSY to be retryable")
+	}
+	if SQLite(sqlite3.Error{Code: sqlite3.ErrConstraint}) || !Any(sqlite3.Error{Code: sqlite3.ErrBusy}) {
+		t.Error("unexpected classification")
+	}
+}
@@ -0,0 +1,84 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+)
+
+var errConflict = errors.New("conflict")
+
+func TestWithTxRetry(t *testing.T) {
+	RunWithSchema(defaultSchema, t, func(db *DB, t *testing.T, now string) {
+		ctx := context.Background()
+		policy := RetryPolicy{
+			MaxAttempts: 3,
+			Retryable:   func(err error) bool { return errors.Is(err, errConflict) },
+		}
+
+		var attempts int
+		err := db.WithTxxRetry(ctx, nil, policy, func(tx *Tx) error {
+			attempts++
+			if attempts < 3 {
+				return errConflict
+			}
+			_, err := tx.Exec(tx.Rebind("INSERT INTO place (country, telcode) VALUES (?, ?)"), "Japan", 81)
+			return err
+		})
+		if err != nil || attempts != 3 {
+			t.Fatalf("expected success on the third attempt, got %v after %d", err, attempts)
+		}
+
+		attempts = 0
+		err = db.WithTxRetry(ctx, nil, policy, func(tx *sql.Tx) error {
+			attempts++
+			return errConflict
+		})
+		if !errors.Is(err, errConflict) || attempts != 3 {
+			t.Errorf("expected to give up after 3 attempts, got %v after %d", err, attempts)
+		}
+
+		attempts = 0
+		errOther := errors.New("other")
+		err = db.WithTxxRetry(ctx, nil, policy, func(tx *Tx) error {
+			attempts++
+			return errOther
+		})
+		if err != errOther || attempts != 1 {
+			t.Errorf("expected errors which are not retryable to be returned, got %v after %d", err, attempts)
+		}
+
+		// cancelling the context stops waiting for the next attempt
+		policy.Backoff = func(int) time.Duration { return time.Hour }
+		cctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		attempts = 0
+		conn, err := db.Connx(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		err = conn.WithTxxRetry(cctx, nil, policy, func(tx *Tx) error {
+			attempts++
+			return errConflict
+		})
+		if !errors.Is(err, errConflict) || attempts != 1 {
+			t.Errorf("expected the retry to be cancelled, got %v after %d", err, attempts)
+		}
+	})
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(10*time.Millisecond, 50*time.Millisecond)
+	for retry, max := range []time.Duration{0, 10, 20, 40, 50, 50} {
+		if retry == 0 {
+			continue
+		}
+		max *= time.Millisecond
+		if d := backoff(retry); d < max/2 || d > max {
+			t.Errorf("retry %d: expected a backoff between %s and %s, got %s", retry, max/2, max, d)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+)
+
+// savepointQueries returns the statements which create, release and roll back
+// to a savepoint for a given drivername.  SQL Server and Oracle have no way to
+// release a savepoint, so release is empty for them.
+func savepointQueries(driverName, name string) (create, release, rollback string) {
+	switch BindType(driverName) {
+	case AT:
+		return "SAVE TRANSACTION " + name, "", "ROLLBACK TRANSACTION " + name
+	case NAMED:
+		return "SAVEPOINT " + name, "", "ROLLBACK TO SAVEPOINT " + name
+	}
+	return "SAVEPOINT " + name, "RELEASE SAVEPOINT " + name, "ROLLBACK TO SAVEPOINT " + name
+}
+
+// Depth returns how many savepoints created by Withx or WithTxx the
+// transaction is currently nested in.
+func (tx *Tx) Depth() int {
+	if tx.depth == nil {
+		return 0
+	}
+	return *tx.depth
+}
+
+// Withx runs handle within a savepoint of this transaction, so that it can be
+// nested within code which already began a transaction.  If handle returns an
+// error or panics, the transaction is rolled back to the savepoint, undoing
+// only the work of handle, and otherwise the savepoint is released.  The
+// transaction itself is neither committed nor rolled back.
+//
+// Withx uses context.Background internally; to specify the context, use
+// WithTxx.
+func (tx *Tx) Withx(handle func(tx *Tx) error) error {
+	return tx.WithTxx(context.Background(), nil, handle)
+}
+
+// WithTxx runs handle within a savepoint of this transaction, like Withx.  The
+// TxOptions are ignored, as a savepoint always shares the isolation level and
+// read only mode of its transaction;  they are accepted so that Tx has the same
+// WithTxx as DB.
+//
+// The rollback to the savepoint does not use ctx, so that it still happens
+// when handle failed because ctx was canceled.  If the rollback fails too, its
+// error is added to the one returned by handle.
+func (tx *Tx) WithTxx(ctx context.Context, opts *sql.TxOptions, handle func(tx *Tx) error) (err error) {
+	if tx.depth == nil {
+		tx.depth = new(int)
+	}
+	*tx.depth++
+	defer func() { *tx.depth-- }()
+
+	create, release, rollback := savepointQueries(tx.driverName, "sqlx_savepoint_"+strconv.Itoa(*tx.depth))
+	if _, err = tx.ExecContext(ctx, create); err != nil {
+		return err
+	}
+
+	done := false
+	defer func() {
+		if done {
+			return
+		}
+		// the savepoint outlives a rollback to it, so release it as well
+		_, rerr := tx.ExecContext(context.Background(), rollback)
+		if rerr == nil && release != "" {
+			_, rerr = tx.ExecContext(context.Background(), release)
+		}
+		if rerr != nil && err != nil {
+			err = fmt.Errorf("%w (rollback to savepoint: %v)", err, rerr)
+		}
+	}()
+	if err = handle(tx); err != nil {
+		return err
+	}
+	done = true
+	if release != "" {
+		_, err = tx.ExecContext(ctx, release)
+	}
+	return err
+}
@@ -0,0 +1,87 @@
+package sqlx
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestSavepointQueries(t *testing.T) {
+	var tests = []struct {
+		driver, create, release, rollback string
+	}{
+		{"postgres", "SAVEPOINT sp", "RELEASE SAVEPOINT sp", "ROLLBACK TO SAVEPOINT sp"},
+		{"mysql", "SAVEPOINT sp", "RELEASE SAVEPOINT sp", "ROLLBACK TO SAVEPOINT sp"},
+		{"sqlserver", "SAVE TRANSACTION sp", "", "ROLLBACK TRANSACTION sp"},
+		{"godror", "SAVEPOINT sp", "", "ROLLBACK TO SAVEPOINT sp"},
+	}
+	for _, test := range tests {
+		create, release, rollback := savepointQueries(test.driver, "sp")
+		if create != test.create || release != test.release || rollback != test.rollback {
+			t.Errorf("%s: unexpected savepoint queries %q, %q, %q", test.driver, create, release, rollback)
+		}
+	}
+}
+
+func TestTxWithx(t *testing.T) {
+	RunWithSchema(defaultSchema, t, func(db *DB, t *testing.T, now string) {
+		insert := db.Rebind("INSERT INTO place (country, telcode) VALUES (?, ?)")
+		errAbort := errors.New("abort")
+
+		err := db.Withx(func(tx *Tx) error {
+			tx.MustExec(insert, "A", 1)
+			err := tx.Withx(func(tx *Tx) error {
+				tx.MustExec(insert, "B", 2)
+				if tx.Depth() != 1 {
+					t.Errorf("expected depth 1, got %d", tx.Depth())
+				}
+				return tx.Unsafe().Withx(func(tx *Tx) error {
+					if tx.Depth() != 2 {
+						t.Errorf("expected depth 2, got %d", tx.Depth())
+					}
+					tx.MustExec(insert, "C", 3)
+					return errAbort
+				})
+			})
+			if err != errAbort {
+				t.Errorf("expected the handler's error, got %v", err)
+			}
+
+			func() {
+				defer func() { recover() }()
+				tx.Withx(func(tx *Tx) error {
+					tx.MustExec(insert, "D", 4)
+					panic("abort")
+				})
+			}()
+
+			// a canceled context still rolls back to the savepoint
+			ctx, cancel := context.WithCancel(context.Background())
+			err = tx.WithTxx(ctx, nil, func(tx *Tx) error {
+				tx.MustExec(insert, "F", 6)
+				cancel()
+				_, err := tx.ExecContext(ctx, insert, "G", 7)
+				return err
+			})
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("expected context.Canceled, got %v", err)
+			}
+
+			return tx.WithTxx(context.Background(), nil, func(tx *Tx) error {
+				_, err := tx.Exec(insert, "E", 5)
+				return err
+			})
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var countries []string
+		if err = db.Select(&countries, "SELECT country FROM place ORDER BY country"); err != nil {
+			t.Fatal(err)
+		}
+		if len(countries) != 2 || countries[0] != "A" || countries[1] != "E" {
+			t.Errorf("expected only A and E to be committed, got %v", countries)
+		}
+	})
+}
@@ -0,0 +1,255 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"sync"
+)
+
+// ErrNoShardKey is returned by a ShardRouter when a query has no shard key.
+var ErrNoShardKey = errors.New("sqlx: no shard key")
+
+// A ShardFunc returns the index of the shard, out of n, which holds key.
+type ShardFunc func(key any, n int) int
+
+// HashShard is a ShardFunc which hashes the key's default format with FNV-1a,
+// so that keys which format the same, eg. the int 1 and the string "1", are on
+// the same shard.
+func HashShard(key any, n int) int {
+	h := fnv.New32a()
+	fmt.Fprint(h, key)
+	return int(h.Sum32() % uint32(n))
+}
+
+type shardKeyContextKey struct{}
+
+// WithShardKey returns a context which routes the queries of a ShardRouter to
+// the shard holding key, eg. a tenant id.
+func WithShardKey(ctx context.Context, key any) context.Context {
+	return context.WithValue(ctx, shardKeyContextKey{}, key)
+}
+
+// ShardKeyFromContext returns the shard key carried by ctx, if any.
+func ShardKeyFromContext(ctx context.Context) (any, bool) {
+	key := ctx.Value(shardKeyContextKey{})
+	return key, key != nil
+}
+
+// ShardRouter routes queries to one of several DBs, the shards, by a key.  The
+// key is taken from the context, as set by WithShardKey, or for named queries
+// from the named parameter set by SetKeyParam if the context has none.
+// ScatterSelect queries every shard.
+//
+// All of the shards should use the same driver, as named queries are bound
+// for the first one's.
+type ShardRouter struct {
+	shards   []*DB
+	shard    ShardFunc
+	keyParam string
+}
+
+// NewShardRouter returns a ShardRouter over shards, which picks the shard for
+// a key with shard, eg. HashShard.  It returns an error if there are no shards.
+func NewShardRouter(shard ShardFunc, shards ...*DB) (*ShardRouter, error) {
+	if len(shards) == 0 {
+		return nil, errors.New("sqlx: NewShardRouter called without shards")
+	}
+	return &ShardRouter{shards: shards, shard: shard}, nil
+}
+
+// SetKeyParam sets the named parameter which holds the shard key of named
+// queries run without a key in their context, eg. "tenant_id" for
+// `:tenant_id`.  It belongs with the setup of the router, before any named
+// query runs on it.
+func (r *ShardRouter) SetKeyParam(name string) {
+	r.keyParam = name
+}
+
+// Shards returns the shards of the router.
+func (r *ShardRouter) Shards() []*DB {
+	return r.shards
+}
+
+// Shard returns the shard which holds key.
+func (r *ShardRouter) Shard(key any) *DB {
+	return r.shards[r.shard(key, len(r.shards))]
+}
+
+// ShardContext returns the shard for the key carried by ctx.
+func (r *ShardRouter) ShardContext(ctx context.Context) (*DB, error) {
+	key, ok := ShardKeyFromContext(ctx)
+	if !ok {
+		return nil, ErrNoShardKey
+	}
+	return r.Shard(key), nil
+}
+
+// shardNamed returns the shard for a named query with arg, by the key carried
+// by ctx or the value of the key parameter in arg.  If arg is an array or
+// slice, all of its elements must be on the same shard.
+func (r *ShardRouter) shardNamed(ctx context.Context, arg any) (*DB, error) {
+	if db, err := r.ShardContext(ctx); err != ErrNoShardKey || r.keyParam == "" {
+		return db, err
+	}
+	m := mapperFor(r.shards[0])
+	names := []string{r.keyParam}
+
+	v := reflect.Indirect(reflect.ValueOf(arg))
+	if k := v.Kind(); k != reflect.Array && k != reflect.Slice {
+		key, err := bindAnyArgs(names, arg, m)
+		if err != nil {
+			return nil, err
+		}
+		return r.Shard(key[0]), nil
+	}
+
+	var db *DB
+	for i := 0; i < v.Len(); i++ {
+		key, err := bindAnyArgs(names, v.Index(i).Interface(), m)
+		if err != nil {
+			return nil, err
+		}
+		if s := r.Shard(key[0]); db == nil {
+			db = s
+		} else if s != db {
+			return nil, errors.New("sqlx: rows of a named query are on different shards")
+		}
+	}
+	if db == nil {
+		return nil, ErrNoShardKey
+	}
+	return db, nil
+}
+
+// GetContext runs Get on the shard for the key carried by ctx.
+func (r *ShardRouter) GetContext(ctx context.Context, dest any, query string, args ...any) error {
+	db, err := r.ShardContext(ctx)
+	if err != nil {
+		return err
+	}
+	return db.GetContext(ctx, dest, query, args...)
+}
+
+// SelectContext runs Select on the shard for the key carried by ctx.
+func (r *ShardRouter) SelectContext(ctx context.Context, dest any, query string, args ...any) error {
+	db, err := r.ShardContext(ctx)
+	if err != nil {
+		return err
+	}
+	return db.SelectContext(ctx, dest, query, args...)
+}
+
+// QueryxContext runs Queryx on the shard for the key carried by ctx.
+func (r *ShardRouter) QueryxContext(ctx context.Context, query string, args ...any) (*Rows, error) {
+	db, err := r.ShardContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return db.QueryxContext(ctx, query, args...)
+}
+
+// QueryRowxContext runs QueryRowx on the shard for the key carried by ctx.
+func (r *ShardRouter) QueryRowxContext(ctx context.Context, query string, args ...any) *Row {
+	db, err := r.ShardContext(ctx)
+	if err != nil {
+		return &Row{err: err}
+	}
+	return db.QueryRowxContext(ctx, query, args...)
+}
+
+// ExecContext runs Exec on the shard for the key carried by ctx.
+func (r *ShardRouter) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	db, err := r.ShardContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return db.ExecContext(ctx, query, args...)
+}
+
+// NamedExecContext runs NamedExec on the shard for the key carried by ctx, or
+// else the value of the key parameter in arg.
+func (r *ShardRouter) NamedExecContext(ctx context.Context, query string, arg any) (sql.Result, error) {
+	db, err := r.shardNamed(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+	return db.NamedExecContext(ctx, query, arg)
+}
+
+// NamedQueryContext runs NamedQuery on the shard for the key carried by ctx,
+// or else the value of the key parameter in arg.
+func (r *ShardRouter) NamedQueryContext(ctx context.Context, query string, arg any) (*Rows, error) {
+	db, err := r.shardNamed(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+	return db.NamedQueryContext(ctx, query, arg)
+}
+
+// WithTxx runs handle within a transaction on the shard for the key carried
+// by ctx.
+func (r *ShardRouter) WithTxx(ctx context.Context, opts *sql.TxOptions, handle func(tx *Tx) error) error {
+	db, err := r.ShardContext(ctx)
+	if err != nil {
+		return err
+	}
+	return db.WithTxx(ctx, opts, handle)
+}
+
+// ScatterSelect runs query on every shard concurrently and scans all of the
+// rows into dest, which must be a pointer to a slice, as Select does.  The rows
+// of each shard follow those of the shards before it, so an ORDER BY only
+// orders the rows within each shard.  If any shard fails, the queries of the
+// others are cancelled and the first error is returned.
+func (r *ShardRouter) ScatterSelect(ctx context.Context, dest any, query string, args ...any) error {
+	value := reflect.ValueOf(dest)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Slice {
+		return errors.New("must pass a non-nil pointer to a slice to ScatterSelect destination")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]reflect.Value, len(r.shards))
+	errs := make([]error, len(r.shards))
+	var wg sync.WaitGroup
+	for i, db := range r.shards {
+		wg.Add(1)
+		go func(i int, db *DB) {
+			defer wg.Done()
+			results[i] = reflect.New(value.Type().Elem())
+			rows, err := db.QueryxContext(ctx, query, args...)
+			if err == nil {
+				err = scanAll(rows, results[i].Interface(), false)
+				rows.Close()
+			}
+			if err != nil {
+				errs[i] = err
+				cancel()
+			}
+		}(i, db)
+	}
+	wg.Wait()
+
+	// the first error which did not come from cancelling the other shards
+	var firstErr error
+	for _, err := range errs {
+		if err != nil && (firstErr == nil || errors.Is(firstErr, context.Canceled)) {
+			firstErr = err
+		}
+	}
+	if firstErr != nil {
+		return firstErr
+	}
+
+	direct := value.Elem()
+	direct.SetLen(0)
+	for _, res := range results {
+		direct.Set(reflect.AppendSlice(direct, res.Elem()))
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package sqlx
+
+import (
+	"context"
+	"sort"
+	"testing"
+)
+
+func TestShardRouter(t *testing.T) {
+	var shards []*DB
+	for i := 0; i < 3; i++ {
+		db, err := Connect("sqlite3", ":memory:")
+		if err != nil {
+			t.Fatal(err)
+		}
+		db.SetMaxOpenConns(1)
+		defer db.Close()
+		db.MustExec("CREATE TABLE tenant (id integer, name text)")
+		shards = append(shards, db)
+	}
+	if _, err := NewShardRouter(HashShard); err == nil {
+		t.Error("expected an error without shards")
+	}
+	r, err := NewShardRouter(func(key any, n int) int { return int(key.(int64)) % n }, shards...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.SetKeyParam("id")
+	ctx := context.Background()
+
+	type tenant struct {
+		ID   int64
+		Name string
+	}
+	for _, tn := range []tenant{{1, "a"}, {2, "b"}, {4, "c"}, {5, "d"}} {
+		if _, err := r.NamedExecContext(ctx, "INSERT INTO tenant (id, name) VALUES (:id, :name)", tn); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var n int
+	if err := r.GetContext(WithShardKey(ctx, int64(1)), &n, "SELECT count(*) FROM tenant"); err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("expected tenants 1 and 4 on the same shard, got %d", n)
+	}
+	if err := r.GetContext(ctx, &n, "SELECT count(*) FROM tenant"); err != ErrNoShardKey {
+		t.Errorf("expected ErrNoShardKey, got %v", err)
+	}
+	_, err = r.NamedExecContext(ctx, "INSERT INTO tenant (id, name) VALUES (:id, :name)", []tenant{{1, "x"}, {2, "y"}})
+	if err == nil {
+		t.Error("expected an error for a batch across shards")
+	}
+
+	var all []tenant
+	if err = r.ScatterSelect(ctx, &all, "SELECT * FROM tenant ORDER BY id"); err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, tn := range all {
+		names = append(names, tn.Name)
+	}
+	sort.Strings(names)
+	if len(all) != 4 || names[0] != "a" || names[3] != "d" {
+		t.Errorf("expected the tenants of every shard, got %v", all)
+	}
+	if err = r.ScatterSelect(ctx, &all, "SELECT * FROM nonexistent"); err == nil {
+		t.Error("expected the error of the shards")
+	}
+}
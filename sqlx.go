@@ -257,6 +257,8 @@ type DB struct {
 	driverName string
 	unsafe     bool
 	Mapper     *reflectx.Mapper
+	hooks      hooks
+	stmts      *stmtCache
 }
 
 // NewDb returns a new sqlx DB wrapper for a pre-existing *sql.DB.  The
@@ -304,12 +306,12 @@ func (db *DB) Rebind(query string) string {
 // sqlx.Stmt and sqlx.Tx which are created from this DB will inherit its
 // safety behavior.
 func (db *DB) Unsafe() *DB {
-	return &DB{DB: db.DB, driverName: db.driverName, unsafe: true, Mapper: db.Mapper}
+	return &DB{DB: db.DB, driverName: db.driverName, unsafe: true, Mapper: db.Mapper, hooks: db.hooks, stmts: db.stmts}
 }
 
 // BindNamed binds a query using the DB driver's bindvar type.
 func (db *DB) BindNamed(query string, arg any) (string, []any, error) {
-	return bindNamedMapper(BindType(db.driverName), query, arg, db.Mapper)
+	return bindNamedMapper(BindType(db.driverName), backslashEscapes(db.driverName), query, arg, db.Mapper)
 }
 
 // NamedQuery using this DB.
@@ -353,7 +355,7 @@ func (db *DB) Beginx() (*Tx, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Tx{Tx: tx, driverName: db.driverName, unsafe: db.unsafe, Mapper: db.Mapper}, err
+	return &Tx{Tx: tx, driverName: db.driverName, unsafe: db.unsafe, Mapper: db.Mapper, hooks: db.hooks, stmts: db.stmts, depth: new(int)}, err
 }
 
 // Begin starts a transaction and do the given handle. The default isolation level
@@ -431,7 +433,7 @@ func (db *DB) WithTxx(ctx context.Context, opts *sql.TxOptions, handle func(tx *
 // and a new arg list that can be executed by a database. The `query` should
 // use the `?` bindVar.  The return value uses had rebinded bindvar type.
 func (db *DB) In(query string, args ...any) (string, []any, error) {
-	q, params, err := In(query, args...)
+	q, params, err := in(query, args, backslashEscapes(db.driverName), false)
 	if err != nil {
 		return "", nil, err
 	}
@@ -463,7 +465,7 @@ func (db *DB) InGet(dest any, query string, args ...any) error {
 // Queryx queries the database and returns an *sqlx.Rows.
 // Any placeholder parameters are replaced with supplied args.
 func (db *DB) Queryx(query string, args ...any) (*Rows, error) {
-	r, err := db.DB.Query(query, args...)
+	r, err := db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -473,7 +475,7 @@ func (db *DB) Queryx(query string, args ...any) (*Rows, error) {
 // QueryRowx queries the database and returns an *sqlx.Row.
 // Any placeholder parameters are replaced with supplied args.
 func (db *DB) QueryRowx(query string, args ...any) *Row {
-	rows, err := db.DB.Query(query, args...)
+	rows, err := db.Query(query, args...)
 	return &Row{rows: rows, err: err, unsafe: db.unsafe, Mapper: db.Mapper}
 }
 
@@ -505,6 +507,7 @@ type Conn struct {
 	driverName string
 	unsafe     bool
 	Mapper     *reflectx.Mapper
+	hooks      hooks
 }
 
 // Tx is an sqlx wrapper around sql.Tx with extra functionality
@@ -513,6 +516,9 @@ type Tx struct {
 	driverName string
 	unsafe     bool
 	Mapper     *reflectx.Mapper
+	hooks      hooks
+	stmts      *stmtCache
+	depth      *int // shared by copies of the Tx, eg. made by Unsafe
 }
 
 // DriverName returns the driverName used by the DB which began this transaction.
@@ -528,12 +534,12 @@ func (tx *Tx) Rebind(query string) string {
 // Unsafe returns a version of Tx which will silently succeed to scan when
 // columns in the SQL result have no fields in the destination struct.
 func (tx *Tx) Unsafe() *Tx {
-	return &Tx{Tx: tx.Tx, driverName: tx.driverName, unsafe: true, Mapper: tx.Mapper}
+	return &Tx{Tx: tx.Tx, driverName: tx.driverName, unsafe: true, Mapper: tx.Mapper, hooks: tx.hooks, stmts: tx.stmts, depth: tx.depth}
 }
 
 // BindNamed binds a query within a transaction's bindvar type.
 func (tx *Tx) BindNamed(query string, arg any) (string, []any, error) {
-	return bindNamedMapper(BindType(tx.driverName), query, arg, tx.Mapper)
+	return bindNamedMapper(BindType(tx.driverName), backslashEscapes(tx.driverName), query, arg, tx.Mapper)
 }
 
 // NamedQuery within a transaction.
@@ -552,7 +558,7 @@ func (tx *Tx) NamedExec(query string, arg any) (sql.Result, error) {
 // and a new arg list that can be executed by a database. The `query` should
 // use the `?` bindVar.  The return value uses had rebinded bindvar type.
 func (tx *Tx) In(query string, args ...any) (string, []any, error) {
-	q, params, err := In(query, args...)
+	q, params, err := in(query, args, backslashEscapes(tx.driverName), false)
 	if err != nil {
 		return "", nil, err
 	}
@@ -590,7 +596,7 @@ func (tx *Tx) Select(dest any, query string, args ...any) error {
 // Queryx within a transaction.
 // Any placeholder parameters are replaced with supplied args.
 func (tx *Tx) Queryx(query string, args ...any) (*Rows, error) {
-	r, err := tx.Tx.Query(query, args...)
+	r, err := tx.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -600,7 +606,7 @@ func (tx *Tx) Queryx(query string, args ...any) (*Rows, error) {
 // QueryRowx within a transaction.
 // Any placeholder parameters are replaced with supplied args.
 func (tx *Tx) QueryRowx(query string, args ...any) *Row {
-	rows, err := tx.Tx.Query(query, args...)
+	rows, err := tx.Query(query, args...)
 	return &Row{rows: rows, err: err, unsafe: tx.unsafe, Mapper: tx.Mapper}
 }
 
@@ -632,17 +638,18 @@ func (tx *Tx) Preparex(query string) (*Stmt, error) {
 // stmt can be either *sql.Stmt or *sqlx.Stmt.
 func (tx *Tx) Stmtx(stmt any) *Stmt {
 	var s *sql.Stmt
+	var query string
 	switch v := stmt.(type) {
 	case Stmt:
-		s = v.Stmt
+		s, query = v.Stmt, v.query
 	case *Stmt:
-		s = v.Stmt
+		s, query = v.Stmt, v.query
 	case *sql.Stmt:
 		s = v
 	default:
 		panic(fmt.Sprintf("non-statement type %v passed to Stmtx", reflect.ValueOf(stmt).Type()))
 	}
-	return &Stmt{Stmt: tx.Stmt(s), Mapper: tx.Mapper}
+	return &Stmt{Stmt: tx.Stmt(s), Mapper: tx.Mapper, hooks: tx.hooks, query: query}
 }
 
 // NamedStmt returns a version of the prepared statement which runs within a transaction.
@@ -651,6 +658,8 @@ func (tx *Tx) NamedStmt(stmt *NamedStmt) *NamedStmt {
 		QueryString: stmt.QueryString,
 		Params:      stmt.Params,
 		Stmt:        tx.Stmtx(stmt.Stmt),
+		unbound:     stmt.unbound,
+		ext:         tx,
 	}
 }
 
@@ -664,12 +673,15 @@ type Stmt struct {
 	*sql.Stmt
 	unsafe bool
 	Mapper *reflectx.Mapper
+	hooks  hooks
+	query  string
+	ref    *stmtRef
 }
 
 // Unsafe returns a version of Stmt which will silently succeed to scan when
 // columns in the SQL result have no fields in the destination struct.
 func (s *Stmt) Unsafe() *Stmt {
-	return &Stmt{Stmt: s.Stmt, unsafe: true, Mapper: s.Mapper}
+	return &Stmt{Stmt: s.Stmt, unsafe: true, Mapper: s.Mapper, hooks: s.hooks, query: s.query, ref: s.ref}
 }
 
 // Select using the prepared statement.
@@ -735,8 +747,9 @@ func (q *qStmt) Exec(query string, args ...any) (sql.Result, error) {
 // during a looped StructScan
 type Rows struct {
 	*sql.Rows
-	unsafe bool
-	Mapper *reflectx.Mapper
+	unsafe    bool
+	qualified bool
+	Mapper    *reflectx.Mapper
 	// these fields cache memory use for a rows during iteration w/ structScan
 	started bool
 	fields  [][]int
@@ -774,7 +787,11 @@ func (r *Rows) StructScan(dest any) error {
 		}
 		m := r.Mapper
 
-		r.fields = m.TraversalsByName(v.Type(), columns)
+		if r.qualified {
+			r.fields = qualifiedTraversals(m, v.Type(), columns)
+		} else {
+			r.fields = m.TraversalsByName(v.Type(), columns)
+		}
 		// if we are not unsafe and are missing fields, return an error
 		if f, err := missingFields(r.fields); err != nil && !r.unsafe {
 			return fmt.Errorf("missing destination name %s in %T", columns[f], dest)
@@ -795,6 +812,16 @@ func (r *Rows) StructScan(dest any) error {
 	return r.Err()
 }
 
+// NextResultSet prepares the next result set for reading, as sql.Rows does,
+// and resets the cached column traversals of StructScan, so that the rows of
+// each result set may be scanned into a different struct type.
+func (r *Rows) NextResultSet() bool {
+	r.started = false
+	r.fields = nil
+	r.values = nil
+	return r.Rows.NextResultSet()
+}
+
 // Connect to a database and verify with a ping.
 func Connect(driverName, dataSourceName string) (*DB, error) {
 	db, err := Open(driverName, dataSourceName)
@@ -820,11 +847,14 @@ func MustConnect(driverName, dataSourceName string) *DB {
 
 // Preparex prepares a statement.
 func Preparex(p Preparer, query string) (*Stmt, error) {
+	if c := stmtCacheFor(p); c != nil {
+		return c.preparex(context.Background(), p, query)
+	}
 	s, err := p.Prepare(query)
 	if err != nil {
 		return nil, err
 	}
-	return &Stmt{Stmt: s, unsafe: isUnsafe(p), Mapper: mapperFor(p)}, err
+	return &Stmt{Stmt: s, unsafe: isUnsafe(p), Mapper: mapperFor(p), hooks: hooksFor(p), query: query}, err
 }
 
 // Select executes a query using the provided Queryer, and StructScans each row
@@ -1164,7 +1194,12 @@ func scanAll(rows rowsi, dest any, structOnly bool) error {
 			m = mapper()
 		}
 
-		fields := m.TraversalsByName(base, columns)
+		var fields [][]int
+		if isQualified(rows) {
+			fields = qualifiedTraversals(m, base, columns)
+		} else {
+			fields = m.TraversalsByName(base, columns)
+		}
 		// if we are not unsafe and are missing fields, return an error
 		if f, err := missingFields(fields); err != nil && !isUnsafe(rows) {
 			return fmt.Errorf("missing destination name %s in %T", columns[f], dest)
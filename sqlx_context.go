@@ -59,16 +59,44 @@ func SelectContext(ctx context.Context, q QueryerContext, dest any, query string
 	return scanAll(rows, dest, false)
 }
 
+// SelectMulti executes a query which returns several result sets, such as a
+// stored procedure or a batch of statements, and scans the rows of each result
+// set into the dest at the same position, as Select does.  It is an error for
+// the query to return fewer result sets than there are dests;  any more are
+// ignored.  Support for multiple result sets is up to the driver.
+func SelectMulti(ctx context.Context, q QueryerContext, query string, args []any, dests ...any) error {
+	rows, err := q.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for i, dest := range dests {
+		if i > 0 && !rows.NextResultSet() {
+			if err = rows.Err(); err != nil {
+				return err
+			}
+			return fmt.Errorf("sqlx: expected %d result sets, got %d", len(dests), i)
+		}
+		if err = scanAll(rows, dest, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // PreparexContext prepares a statement.
 //
 // The provided context is used for the preparation of the statement, not for
 // the execution of the statement.
 func PreparexContext(ctx context.Context, p PreparerContext, query string) (*Stmt, error) {
+	if c := stmtCacheFor(p); c != nil {
+		return c.preparex(ctx, p, query)
+	}
 	s, err := p.PrepareContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
-	return &Stmt{Stmt: s, unsafe: isUnsafe(p), Mapper: mapperFor(p)}, err
+	return &Stmt{Stmt: s, unsafe: isUnsafe(p), Mapper: mapperFor(p), hooks: hooksFor(p), query: query}, err
 }
 
 // GetContext does a QueryRow using the provided Queryer, and scans the
@@ -156,7 +184,7 @@ func (db *DB) PreparexContext(ctx context.Context, query string) (*Stmt, error)
 // QueryxContext queries the database and returns an *sqlx.Rows.
 // Any placeholder parameters are replaced with supplied args.
 func (db *DB) QueryxContext(ctx context.Context, query string, args ...any) (*Rows, error) {
-	r, err := db.DB.QueryContext(ctx, query, args...)
+	r, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -166,7 +194,7 @@ func (db *DB) QueryxContext(ctx context.Context, query string, args ...any) (*Ro
 // QueryRowxContext queries the database and returns an *sqlx.Row.
 // Any placeholder parameters are replaced with supplied args.
 func (db *DB) QueryRowxContext(ctx context.Context, query string, args ...any) *Row {
-	rows, err := db.DB.QueryContext(ctx, query, args...)
+	rows, err := db.QueryContext(ctx, query, args...)
 	return &Row{rows: rows, err: err, unsafe: db.unsafe, Mapper: db.Mapper}
 }
 
@@ -203,7 +231,7 @@ func (db *DB) BeginTxx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Tx{Tx: tx, driverName: db.driverName, unsafe: db.unsafe, Mapper: db.Mapper}, err
+	return &Tx{Tx: tx, driverName: db.driverName, unsafe: db.unsafe, Mapper: db.Mapper, hooks: db.hooks, stmts: db.stmts, depth: new(int)}, err
 }
 
 // Connx returns an *sqlx.Conn instead of an *sql.Conn.
@@ -213,7 +241,7 @@ func (db *DB) Connx(ctx context.Context) (*Conn, error) {
 		return nil, err
 	}
 
-	return &Conn{Conn: conn, driverName: db.driverName, unsafe: db.unsafe, Mapper: db.Mapper}, nil
+	return &Conn{Conn: conn, driverName: db.driverName, unsafe: db.unsafe, Mapper: db.Mapper, hooks: db.hooks}, nil
 }
 
 // BeginTxx begins a transaction and returns an *sqlx.Tx instead of an
@@ -228,7 +256,7 @@ func (c *Conn) BeginTxx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Tx{Tx: tx, driverName: c.driverName, unsafe: c.unsafe, Mapper: c.Mapper}, err
+	return &Tx{Tx: tx, driverName: c.driverName, unsafe: c.unsafe, Mapper: c.Mapper, hooks: c.hooks, depth: new(int)}, err
 }
 
 // With starts a transaction and do the give handle.
@@ -337,7 +365,7 @@ func (c *Conn) PreparexContext(ctx context.Context, query string) (*Stmt, error)
 // QueryxContext queries the database and returns an *sqlx.Rows.
 // Any placeholder parameters are replaced with supplied args.
 func (c *Conn) QueryxContext(ctx context.Context, query string, args ...any) (*Rows, error) {
-	r, err := c.Conn.QueryContext(ctx, query, args...)
+	r, err := c.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -347,7 +375,7 @@ func (c *Conn) QueryxContext(ctx context.Context, query string, args ...any) (*R
 // QueryRowxContext queries the database and returns an *sqlx.Row.
 // Any placeholder parameters are replaced with supplied args.
 func (c *Conn) QueryRowxContext(ctx context.Context, query string, args ...any) *Row {
-	rows, err := c.Conn.QueryContext(ctx, query, args...)
+	rows, err := c.QueryContext(ctx, query, args...)
 	return &Row{rows: rows, err: err, unsafe: c.unsafe, Mapper: c.Mapper}
 }
 
@@ -360,17 +388,18 @@ func (c *Conn) Rebind(query string) string {
 // transaction. Provided stmt can be either *sql.Stmt or *sqlx.Stmt.
 func (tx *Tx) StmtxContext(ctx context.Context, stmt any) *Stmt {
 	var s *sql.Stmt
+	var query string
 	switch v := stmt.(type) {
 	case Stmt:
-		s = v.Stmt
+		s, query = v.Stmt, v.query
 	case *Stmt:
-		s = v.Stmt
+		s, query = v.Stmt, v.query
 	case *sql.Stmt:
 		s = v
 	default:
 		panic(fmt.Sprintf("non-statement type %v passed to Stmtx", reflect.ValueOf(stmt).Type()))
 	}
-	return &Stmt{Stmt: tx.StmtContext(ctx, s), Mapper: tx.Mapper}
+	return &Stmt{Stmt: tx.StmtContext(ctx, s), Mapper: tx.Mapper, hooks: tx.hooks, query: query}
 }
 
 // NamedStmtContext returns a version of the prepared statement which runs
@@ -380,6 +409,8 @@ func (tx *Tx) NamedStmtContext(ctx context.Context, stmt *NamedStmt) *NamedStmt
 		QueryString: stmt.QueryString,
 		Params:      stmt.Params,
 		Stmt:        tx.StmtxContext(ctx, stmt.Stmt),
+		unbound:     stmt.unbound,
+		ext:         tx,
 	}
 }
 
@@ -405,7 +436,7 @@ func (tx *Tx) MustExecContext(ctx context.Context, query string, args ...any) sq
 // QueryxContext within a transaction and context.
 // Any placeholder parameters are replaced with supplied args.
 func (tx *Tx) QueryxContext(ctx context.Context, query string, args ...any) (*Rows, error) {
-	r, err := tx.Tx.QueryContext(ctx, query, args...)
+	r, err := tx.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -428,7 +459,7 @@ func (tx *Tx) GetContext(ctx context.Context, dest any, query string, args ...an
 // QueryRowxContext within a transaction and context.
 // Any placeholder parameters are replaced with supplied args.
 func (tx *Tx) QueryRowxContext(ctx context.Context, query string, args ...any) *Row {
-	rows, err := tx.Tx.QueryContext(ctx, query, args...)
+	rows, err := tx.QueryContext(ctx, query, args...)
 	return &Row{rows: rows, err: err, unsafe: tx.unsafe, Mapper: tx.Mapper}
 }
 
@@ -13,8 +13,10 @@ package sqlx
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"strings"
 	"testing"
@@ -1422,3 +1424,134 @@ func TestConn(t *testing.T) {
 		}
 	})
 }
+
+// resultSets is a driver whose queries return the result sets of a query in
+// the form "a,b:1,2;3,4|c:5", ie. result sets separated by '|', each with its
+// columns before the ':' and its rows separated by ';'.
+type resultSets struct{}
+
+func (resultSets) Open(string) (driver.Conn, error) { return resultSetsConn{}, nil }
+
+type resultSetsConn struct{}
+
+func (resultSetsConn) Prepare(query string) (driver.Stmt, error) { return resultSetsStmt(query), nil }
+func (resultSetsConn) Close() error                              { return nil }
+func (resultSetsConn) Begin() (driver.Tx, error)                 { return nil, driver.ErrSkip }
+
+type resultSetsStmt string
+
+func (resultSetsStmt) Close() error  { return nil }
+func (resultSetsStmt) NumInput() int { return -1 }
+func (resultSetsStmt) Exec([]driver.Value) (driver.Result, error) {
+	return driver.RowsAffected(0), nil
+}
+func (s resultSetsStmt) Query([]driver.Value) (driver.Rows, error) {
+	return &resultSetsRows{sets: strings.Split(string(s), "|")}, nil
+}
+
+type resultSetsRows struct {
+	sets    []string
+	columns []string
+	rows    []string
+}
+
+func (r *resultSetsRows) Columns() []string {
+	if r.columns == nil {
+		parts := strings.SplitN(r.sets[0], ":", 2)
+		r.columns = strings.Split(parts[0], ",")
+		if len(parts) > 1 && parts[1] != "" {
+			r.rows = strings.Split(parts[1], ";")
+		}
+	}
+	return r.columns
+}
+
+func (r *resultSetsRows) Close() error { return nil }
+
+func (r *resultSetsRows) Next(dest []driver.Value) error {
+	r.Columns()
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	for i, v := range strings.Split(r.rows[0], ",") {
+		dest[i] = v
+	}
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func (r *resultSetsRows) HasNextResultSet() bool { return len(r.sets) > 1 }
+
+func (r *resultSetsRows) NextResultSet() error {
+	if len(r.sets) < 2 {
+		return io.EOF
+	}
+	r.sets, r.columns, r.rows = r.sets[1:], nil, nil
+	return nil
+}
+
+func init() {
+	sql.Register("sqlx_resultsets", resultSets{})
+}
+
+func TestSelectMulti(t *testing.T) {
+	db := MustOpen("sqlx_resultsets", "")
+	defer db.Close()
+	ctx := context.Background()
+
+	type user struct {
+		ID   int    `db:"id"`
+		Name string `db:"name"`
+	}
+	type order struct {
+		ID     int `db:"id"`
+		UserID int `db:"user_id"`
+		Total  int `db:"total"`
+	}
+	var users []user
+	var orders []*order
+	var counts []int64
+	err := SelectMulti(ctx, db, "id,name:1,ann;2,bob|id,user_id,total:7,1,30;8,2,12|n:2", nil, &users, &orders, &counts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 || users[1].Name != "bob" {
+		t.Errorf("unexpected users %#v", users)
+	}
+	if len(orders) != 2 || orders[1].UserID != 2 || orders[1].Total != 12 {
+		t.Errorf("unexpected orders %v and %v", orders[0], orders[1])
+	}
+	if len(counts) != 1 || counts[0] != 2 {
+		t.Errorf("unexpected counts %v", counts)
+	}
+
+	err = SelectMulti(ctx, db, "id,name:1,ann", nil, &users, &orders)
+	if err == nil || !strings.Contains(err.Error(), "expected 2 result sets, got 1") {
+		t.Errorf("expected a missing result set error, got %v", err)
+	}
+
+	// StructScan of a Rows maps the columns of each result set
+	rows, err := db.QueryxContext(ctx, "id,name:1,ann|id,user_id,total:7,1,30")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var u user
+	var o order
+	for rows.Next() {
+		if err = rows.StructScan(&u); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !rows.NextResultSet() {
+		t.Fatalf("expected a second result set, %v", rows.Err())
+	}
+	for rows.Next() {
+		if err = rows.StructScan(&o); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if u.Name != "ann" || o.Total != 30 || rows.NextResultSet() {
+		t.Errorf("unexpected results %#v and %#v", u, o)
+	}
+}
@@ -1367,7 +1367,7 @@ func TestBindMap(t *testing.T) {
 		"last":  "Moiron",
 	}
 
-	bq, args, _ := bindMap(QUESTION, q1, am)
+	bq, args, _ := bindMap(QUESTION, false, q1, am)
 	expect := `INSERT INTO foo (a, b, c, d) VALUES (?, ?, ?, ?)`
 	if bq != expect {
 		t.Errorf("Interpolation of query failed: got `%v`, expected `%v`\n", bq, expect)
@@ -1629,7 +1629,7 @@ func TestBindStruct(t *testing.T) {
 
 	am := tt{"Jason Moiron", 30, "Jason", "Moiron"}
 
-	bq, args, _ := bindStruct(QUESTION, q1, am, mapper())
+	bq, args, _ := bindStruct(QUESTION, false, q1, am, mapper())
 	expect := `INSERT INTO foo (a, b, c, d) VALUES (?, ?, ?, ?)`
 	if bq != expect {
 		t.Errorf("Interpolation of query failed: got `%v`, expected `%v`\n", bq, expect)
@@ -1652,7 +1652,7 @@ func TestBindStruct(t *testing.T) {
 	}
 
 	am2 := tt2{"Hello", "World"}
-	bq, args, _ = bindStruct(QUESTION, "INSERT INTO foo (a, b) VALUES (:field_2, :field_1)", am2, mapper())
+	bq, args, _ = bindStruct(QUESTION, false, "INSERT INTO foo (a, b) VALUES (:field_2, :field_1)", am2, mapper())
 	expect = `INSERT INTO foo (a, b) VALUES (?, ?)`
 	if bq != expect {
 		t.Errorf("Interpolation of query failed: got `%v`, expected `%v`\n", bq, expect)
@@ -1669,7 +1669,7 @@ func TestBindStruct(t *testing.T) {
 	am3.Field1 = "Hello"
 	am3.Field2 = "World"
 
-	bq, args, err = bindStruct(QUESTION, "INSERT INTO foo (a, b, c) VALUES (:name, :field_1, :field_2)", am3, mapper())
+	bq, args, err = bindStruct(QUESTION, false, "INSERT INTO foo (a, b, c) VALUES (:name, :field_1, :field_2)", am3, mapper())
 
 	if err != nil {
 		t.Fatal(err)
@@ -1745,14 +1745,14 @@ func BenchmarkBindStruct(b *testing.B) {
 	am := t{"Jason Moiron", 30, "Jason", "Moiron"}
 	b.StartTimer()
 	for i := 0; i < b.N; i++ {
-		bindStruct(DOLLAR, q1, am, mapper())
+		bindStruct(DOLLAR, false, q1, am, mapper())
 	}
 }
 
 func TestBindNamedMapper(t *testing.T) {
 	type A map[string]any
 	m := reflectx.NewMapperFunc("db", NameMapper)
-	query, args, err := bindNamedMapper(DOLLAR, `select :x`, A{
+	query, args, err := bindNamedMapper(DOLLAR, false, `select :x`, A{
 		"x": "X!",
 	}, m)
 	if err != nil {
@@ -1765,7 +1765,7 @@ func TestBindNamedMapper(t *testing.T) {
 		t.Errorf("\ngot:  %q\nwant: %q", got, want)
 	}
 
-	_, _, err = bindNamedMapper(DOLLAR, `select :x`, map[string]string{
+	_, _, err = bindNamedMapper(DOLLAR, false, `select :x`, map[string]string{
 		"x": "X!",
 	}, m)
 	if err == nil {
@@ -1787,7 +1787,7 @@ func BenchmarkBindMap(b *testing.B) {
 	}
 	b.StartTimer()
 	for i := 0; i < b.N; i++ {
-		bindMap(DOLLAR, q1, am)
+		bindMap(DOLLAR, false, q1, am)
 	}
 }
 
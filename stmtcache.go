@@ -0,0 +1,201 @@
+package sqlx
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"sync"
+)
+
+// stmtCache is a bounded cache of prepared statements keyed by their query,
+// which evicts the least recently used statement when it is full.  Statements
+// are reference counted, so that an evicted statement is only closed once
+// every Stmt which was returned for it has been closed.
+type stmtCache struct {
+	db *sql.DB
+
+	mu    sync.Mutex
+	size  int
+	lru   *list.List // of *cachedStmt, most recently used first
+	items map[string]*list.Element
+}
+
+type cachedStmt struct {
+	query   string
+	stmt    *sql.Stmt
+	refs    int
+	evicted bool
+}
+
+// stmtRef is the reference a cached Stmt holds on its statement.  It is shared
+// by copies of the Stmt, eg. made by Unsafe, so that it is only released once.
+type stmtRef struct {
+	cache *stmtCache
+	entry *cachedStmt
+	once  sync.Once
+}
+
+func (r *stmtRef) release() (err error) {
+	r.once.Do(func() { err = r.cache.release(r.entry) })
+	return err
+}
+
+func newStmtCache(db *sql.DB, size int) *stmtCache {
+	return &stmtCache{db: db, size: size, lru: list.New(), items: make(map[string]*list.Element)}
+}
+
+// acquire returns the cached statement for query, preparing it if it is not
+// cached, with a reference which must be released.
+func (c *stmtCache) acquire(ctx context.Context, query string) (*cachedStmt, error) {
+	if e := c.cached(query); e != nil {
+		return e, nil
+	}
+
+	// prepare without holding the lock;  if the same query was prepared
+	// concurrently, the first one to be cached wins.
+	s, err := c.db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[query]; ok {
+		s.Close()
+		c.lru.MoveToFront(el)
+		e := el.Value.(*cachedStmt)
+		e.refs++
+		return e, nil
+	}
+	e := &cachedStmt{query: query, stmt: s, refs: 1}
+	c.items[query] = c.lru.PushFront(e)
+	c.evict()
+	return e, nil
+}
+
+// cached returns the cached statement for query with a reference which must
+// be released, or nil if it is not cached.
+func (c *stmtCache) cached(query string) *cachedStmt {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[query]
+	if !ok {
+		return nil
+	}
+	c.lru.MoveToFront(el)
+	e := el.Value.(*cachedStmt)
+	e.refs++
+	return e
+}
+
+func (c *stmtCache) release(e *cachedStmt) error {
+	c.mu.Lock()
+	e.refs--
+	closing := e.evicted && e.refs == 0
+	c.mu.Unlock()
+	if closing {
+		return e.stmt.Close()
+	}
+	return nil
+}
+
+// evict removes the least recently used statements until the cache fits its
+// size, closing those which are not referenced.  c.mu must be held.
+func (c *stmtCache) evict() {
+	for c.lru.Len() > c.size {
+		e := c.lru.Remove(c.lru.Back()).(*cachedStmt)
+		delete(c.items, e.query)
+		e.evicted = true
+		if e.refs == 0 {
+			e.stmt.Close()
+		}
+	}
+}
+
+func (c *stmtCache) resize(size int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.size = size
+	c.evict()
+}
+
+// preparex returns a Stmt for query on p, a DB or Tx, using the cached
+// statement.  Within a transaction, a cached statement is rebound to the
+// transaction with Tx.StmtContext, and closed along with it.  A transaction
+// does not add statements to the cache, as preparing them on the DB needs a
+// second connection while the transaction holds one;  queries which are not
+// cached yet are prepared on the transaction instead.
+func (c *stmtCache) preparex(ctx context.Context, p any, query string) (*Stmt, error) {
+	s := &Stmt{unsafe: isUnsafe(p), Mapper: mapperFor(p), hooks: hooksFor(p), query: query}
+	if tx, ok := p.(*Tx); ok {
+		e := c.cached(query)
+		if e == nil {
+			stmt, err := tx.PrepareContext(ctx, query)
+			if err != nil {
+				return nil, err
+			}
+			s.Stmt = stmt
+			return s, nil
+		}
+		// the transaction's statement keeps the cached one open until it is
+		// closed, so the reference is not needed beyond this point.
+		s.Stmt = tx.StmtContext(ctx, e.stmt)
+		return s, c.release(e)
+	}
+
+	e, err := c.acquire(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	s.Stmt = e.stmt
+	s.ref = &stmtRef{cache: c, entry: e}
+	return s, nil
+}
+
+// determine the statement cache of any of our extensions
+func stmtCacheFor(i any) *stmtCache {
+	switch v := i.(type) {
+	case *DB:
+		return v.stmts
+	case *Tx:
+		return v.stmts
+	default:
+		return nil
+	}
+}
+
+// SetStmtCacheSize sets the number of prepared statements which are cached by
+// this DB.  Once it is set, Preparex and PrepareNamed return statements from
+// the cache, keyed by their query, preparing them only if they are not cached,
+// and the least recently used statement is closed when the cache is full.
+// Closing a cached Stmt returns it to the cache instead.  Transactions begun
+// from the DB rebind the statements which are cached to the transaction.
+//
+// If n <= 0, statements are no longer cached, and those which are cached are
+// closed.  A cache can be resized while the DB is in use, but turning it on or
+// off swaps the cache which Preparex and Beginx look in, so do that before the
+// DB is shared.  A Tx begun before keeps the cache it was begun with.
+func (db *DB) SetStmtCacheSize(n int) {
+	if n <= 0 {
+		if db.stmts != nil {
+			db.stmts.resize(0)
+			db.stmts = nil
+		}
+		return
+	}
+	if db.stmts == nil {
+		db.stmts = newStmtCache(db.DB, n)
+		return
+	}
+	db.stmts.resize(n)
+}
+
+// Close closes the statement.  If it was returned by a DB's statement cache,
+// it is released back to the cache instead, and only closed once it is both
+// evicted and released.
+func (s *Stmt) Close() error {
+	if s.ref != nil {
+		return s.ref.release()
+	}
+	return s.Stmt.Close()
+}
@@ -0,0 +1,81 @@
+package sqlx
+
+import (
+	"testing"
+)
+
+func TestStmtCache(t *testing.T) {
+	RunWithSchema(defaultSchema, t, func(db *DB, t *testing.T, now string) {
+		loadDefaultFixture(db, t)
+		db.SetStmtCacheSize(2)
+		defer db.SetStmtCacheSize(0)
+
+		q1 := db.Rebind("SELECT count(*) FROM person WHERE first_name = ?")
+		q2 := db.Rebind("SELECT count(*) FROM person WHERE last_name = ?")
+		q3 := db.Rebind("SELECT count(*) FROM place WHERE country = ?")
+
+		s1, err := db.Preparex(q1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s1.Close()
+		s2, err := db.Preparex(q1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s1.Stmt != s2.Stmt {
+			t.Error("expected the cached statement to be reused")
+		}
+
+		// s2 is still referenced when it is evicted, so it stays usable
+		for _, q := range []string{q2, q3} {
+			s, err := db.Preparex(q)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s.Close()
+		}
+		if _, ok := db.stmts.items[q1]; ok {
+			t.Error("expected the least recently used statement to be evicted")
+		}
+		var n int
+		if err = s2.Get(&n, "Jason"); err != nil {
+			t.Fatal(err)
+		}
+		if n != 1 {
+			t.Errorf("expected 1, got %d", n)
+		}
+		s2.Unsafe().Close()
+		s2.Close()
+		if _, err = s2.Stmt.Exec("Jason"); err == nil {
+			t.Error("expected the evicted statement to be closed once released")
+		}
+
+		// named statements are cached by their compiled query
+		ns, err := db.PrepareNamed("SELECT count(*) FROM person WHERE first_name = :first_name")
+		if err != nil {
+			t.Fatal(err)
+		}
+		ns.Close()
+		if _, ok := db.stmts.items[ns.QueryString]; !ok {
+			t.Error("expected the named statement to be cached")
+		}
+
+		// transactions rebind cached statements
+		tx := db.MustBegin()
+		defer tx.Rollback()
+		s, err := tx.Preparex(q3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = s.Get(&n, "Singapore"); err != nil {
+			t.Fatal(err)
+		}
+		if n != 1 {
+			t.Errorf("expected 1, got %d", n)
+		}
+		if s.ref != nil || db.stmts.items[q3].Value.(*cachedStmt).refs != 0 {
+			t.Error("expected the transaction not to hold a reference to the cached statement")
+		}
+	})
+}
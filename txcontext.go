@@ -0,0 +1,54 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+)
+
+type txContextKey struct{}
+
+// NewTxContext returns a context which carries tx, so that functions which are
+// passed the context can run their queries within the transaction by getting
+// their Queryable from DB.Queryable.
+func NewTxContext(ctx context.Context, tx *Tx) context.Context {
+	return context.WithValue(ctx, txContextKey{}, tx)
+}
+
+// TxFromContext returns the transaction carried by ctx, if any.
+func TxFromContext(ctx context.Context) (*Tx, bool) {
+	tx, ok := ctx.Value(txContextKey{}).(*Tx)
+	return tx, ok && tx != nil
+}
+
+// Queryable returns the transaction carried by ctx if there is one, and this
+// DB otherwise.  A function which gets its Queryable from the context takes
+// part in any transaction its caller began with RunInTx or NewTxContext:
+//
+//	func (r *Repo) Create(ctx context.Context, p *Person) error {
+//		_, err := r.db.Queryable(ctx).NamedExecContext(ctx, insertPerson, p)
+//		return err
+//	}
+//
+// A context carries a single transaction, which should be one begun from this
+// DB.
+func (db *DB) Queryable(ctx context.Context) Queryable {
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx
+	}
+	return db
+}
+
+// RunInTx runs fn within a transaction which is carried by the context passed
+// to it.  If ctx already carries a transaction, fn runs within a savepoint of
+// it, as with Tx.WithTxx, and opts are ignored.  Otherwise, a new transaction
+// is begun with opts and committed if fn succeeds, as with DB.WithTxx.  In
+// either case, the work of fn is rolled back if it returns an error or panics.
+func (db *DB) RunInTx(ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context) error) error {
+	handle := func(tx *Tx) error {
+		return fn(NewTxContext(ctx, tx))
+	}
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx.WithTxx(ctx, opts, handle)
+	}
+	return db.WithTxx(ctx, opts, handle)
+}
@@ -0,0 +1,69 @@
+package sqlx
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestTxContext(t *testing.T) {
+	RunWithSchema(defaultSchema, t, func(db *DB, t *testing.T, now string) {
+		ctx := context.Background()
+		if _, ok := TxFromContext(ctx); ok {
+			t.Error("expected no transaction in the background context")
+		}
+		if db.Queryable(ctx) != Queryable(db) {
+			t.Error("expected the DB outside of a transaction")
+		}
+
+		insert := func(ctx context.Context, country string) error {
+			_, err := db.Queryable(ctx).ExecContext(ctx, db.Rebind("INSERT INTO place (country, telcode) VALUES (?, 1)"), country)
+			return err
+		}
+		errAbort := errors.New("abort")
+
+		err := db.RunInTx(ctx, nil, func(ctx context.Context) error {
+			tx, ok := TxFromContext(ctx)
+			if !ok || db.Queryable(ctx) != Queryable(tx) {
+				t.Fatal("expected the transaction in the context")
+			}
+			if err := insert(ctx, "A"); err != nil {
+				return err
+			}
+			err := db.RunInTx(ctx, nil, func(ctx context.Context) error {
+				if inner, _ := TxFromContext(ctx); inner != tx || tx.Depth() != 1 {
+					t.Error("expected the nested call to use a savepoint of the transaction")
+				}
+				if err := insert(ctx, "B"); err != nil {
+					return err
+				}
+				return errAbort
+			})
+			if err != errAbort {
+				t.Errorf("expected the nested error, got %v", err)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = db.RunInTx(ctx, nil, func(ctx context.Context) error {
+			if err := insert(ctx, "C"); err != nil {
+				return err
+			}
+			return errAbort
+		})
+		if err != errAbort {
+			t.Errorf("expected the error, got %v", err)
+		}
+
+		var countries []string
+		if err = db.Select(&countries, "SELECT country FROM place"); err != nil {
+			t.Fatal(err)
+		}
+		if len(countries) != 1 || countries[0] != "A" {
+			t.Errorf("expected only A to be committed, got %v", countries)
+		}
+	})
+}
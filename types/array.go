@@ -0,0 +1,501 @@
+package types
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	_scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	_valuerType  = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+	_bytesType   = reflect.TypeOf([]byte(nil))
+	_arrayType   = reflect.TypeOf((*interface{ isArray() })(nil)).Elem()
+	_timeType    = reflect.TypeOf(time.Time{})
+)
+
+// Array[T] is a slice which is submitted to and Scanned from postgres array
+// columns, eg. text[], int8[] or uuid[], in the array literal format:
+//
+//	var tags types.Array[string]
+//	err := db.Get(&tags, "SELECT tags FROM post WHERE id = $1", id)
+//
+// T may be a string, []byte, bool, integer, float or time.Time type, a type
+// which implements sql.Scanner and driver.Valuer, eg. a uuid type, a pointer to
+// any of these, so that NULL elements are nil, or a slice of any of these for
+// the dimensions of a multidimensional array, eg. Array[[]int] for int[][].  A
+// NULL element can also be scanned into a Scanner which accepts nil.  Elements
+// which are a []byte, or whose Value is one, are bytea.
+//
+// As an Array[T] is a driver.Valuer, sqlx.In does not expand it but passes it
+// as a single argument, eg. for `WHERE id = ANY(?)`.  A nil Array[T] is NULL.
+type Array[T any] []T
+
+func (Array[T]) isArray() {}
+
+// Value implements the driver.Valuer interface, formatting the Array[T] as a
+// postgres array literal.
+func (a Array[T]) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+	var b strings.Builder
+	if err := appendArray(&b, reflect.ValueOf([]T(a))); err != nil {
+		return nil, err
+	}
+	return b.String(), nil
+}
+
+// Scan implements the sql.Scanner interface, parsing the postgres array
+// literal coming off the wire into the Array[T].  A NULL source sets the
+// Array[T] to nil.
+func (a *Array[T]) Scan(src any) error {
+	var source string
+	switch t := src.(type) {
+	case string:
+		source = t
+	case []byte:
+		source = string(t)
+	case nil:
+		*a = nil
+		return nil
+	default:
+		return errors.New("incompatible type for Array")
+	}
+	p := arrayParser{src: source}
+	elems, err := p.parse()
+	if err != nil {
+		return err
+	}
+	v := reflect.New(reflect.TypeOf([]T(nil))).Elem()
+	if err = assignArray(v, elems); err != nil {
+		return err
+	}
+	*a = v.Interface().([]T)
+	return nil
+}
+
+// arrayElem is an element of a parsed array literal:  a string, NULL, or the
+// elements of a nested array.
+type arrayElem struct {
+	s     string
+	null  bool
+	array []arrayElem
+	isArr bool
+}
+
+// arrayParser parses the postgres array literal format, eg.
+//
+//	{1,NULL,"a \"quoted\", string",{nested}}
+type arrayParser struct {
+	src string
+	pos int
+}
+
+func (p *arrayParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("types: invalid array literal %q at %d: %s", p.src, p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *arrayParser) skipSpace() {
+	for p.pos < len(p.src) && isArraySpace(p.src[p.pos]) {
+		p.pos++
+	}
+}
+
+func isArraySpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\v' || c == '\f'
+}
+
+func (p *arrayParser) parse() ([]arrayElem, error) {
+	p.skipSpace()
+	// skip explicit bounds, eg. [0:1]={1,2}
+	if p.pos < len(p.src) && p.src[p.pos] == '[' {
+		eq := strings.IndexByte(p.src[p.pos:], '=')
+		if eq < 0 {
+			return nil, p.errorf("expected '=' after the dimensions")
+		}
+		p.pos += eq + 1
+		p.skipSpace()
+	}
+	elems, err := p.parseArray()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos != len(p.src) {
+		return nil, p.errorf("unexpected %q after the array", p.src[p.pos])
+	}
+	return elems, nil
+}
+
+func (p *arrayParser) parseArray() ([]arrayElem, error) {
+	if p.pos >= len(p.src) || p.src[p.pos] != '{' {
+		return nil, p.errorf("expected '{'")
+	}
+	p.pos++
+	p.skipSpace()
+	elems := []arrayElem{}
+	if p.pos < len(p.src) && p.src[p.pos] == '}' {
+		p.pos++
+		return elems, nil
+	}
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.src) {
+			return nil, p.errorf("unterminated array")
+		}
+		var e arrayElem
+		var err error
+		switch p.src[p.pos] {
+		case '{':
+			e.isArr = true
+			e.array, err = p.parseArray()
+		case '"':
+			e.s, err = p.parseQuoted()
+		default:
+			e.s, err = p.parseUnquoted()
+			e.null = strings.EqualFold(e.s, "NULL")
+		}
+		if err != nil {
+			return nil, err
+		}
+		elems = append(elems, e)
+
+		p.skipSpace()
+		if p.pos >= len(p.src) {
+			return nil, p.errorf("unterminated array")
+		}
+		switch p.src[p.pos] {
+		case ',':
+			p.pos++
+		case '}':
+			p.pos++
+			return elems, nil
+		default:
+			return nil, p.errorf("unexpected %q", p.src[p.pos])
+		}
+	}
+}
+
+func (p *arrayParser) parseQuoted() (string, error) {
+	p.pos++
+	var b strings.Builder
+	for p.pos < len(p.src) {
+		switch c := p.src[p.pos]; c {
+		case '\\':
+			p.pos++
+			if p.pos >= len(p.src) {
+				return "", p.errorf("unterminated escape")
+			}
+			b.WriteByte(p.src[p.pos])
+		case '"':
+			p.pos++
+			return b.String(), nil
+		default:
+			b.WriteByte(c)
+		}
+		p.pos++
+	}
+	return "", p.errorf("unterminated quoted element")
+}
+
+func (p *arrayParser) parseUnquoted() (string, error) {
+	var b strings.Builder
+	for p.pos < len(p.src) {
+		switch c := p.src[p.pos]; c {
+		case ',', '}':
+			if b.Len() == 0 {
+				return "", p.errorf("empty element")
+			}
+			return strings.TrimRightFunc(b.String(), func(r rune) bool { return r < 0x80 && isArraySpace(byte(r)) }), nil
+		case '{', '"':
+			return "", p.errorf("unexpected %q in unquoted element", c)
+		case '\\':
+			p.pos++
+			if p.pos >= len(p.src) {
+				return "", p.errorf("unterminated escape")
+			}
+			b.WriteByte(p.src[p.pos])
+		default:
+			b.WriteByte(c)
+		}
+		p.pos++
+	}
+	return "", p.errorf("unterminated array")
+}
+
+// isArraySlice returns whether t is a nested dimension of an array rather
+// than an element type.
+func isArraySlice(t reflect.Type) bool {
+	if t.Kind() != reflect.Slice || t == _bytesType {
+		return false
+	}
+	return t.Implements(_arrayType) || !reflect.PtrTo(t).Implements(_scannerType)
+}
+
+// assignArray sets the slice v to elems.
+func assignArray(v reflect.Value, elems []arrayElem) error {
+	s := reflect.MakeSlice(v.Type(), len(elems), len(elems))
+	for i, e := range elems {
+		if err := assignElem(s.Index(i), e); err != nil {
+			return err
+		}
+	}
+	v.Set(s)
+	return nil
+}
+
+func assignElem(v reflect.Value, e arrayElem) error {
+	t := v.Type()
+	if e.isArr {
+		// a nested Array[T] is a Scanner, but is assigned in place
+		if t.Kind() != reflect.Slice || t == _bytesType {
+			return fmt.Errorf("types: cannot scan a nested array into %s", t)
+		}
+		return assignArray(v, e.array)
+	}
+	if e.null {
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+			v.Set(reflect.Zero(t))
+			return nil
+		}
+	}
+	if s, ok := v.Addr().Interface().(sql.Scanner); ok {
+		if e.null {
+			return s.Scan(nil)
+		}
+		return s.Scan(e.s)
+	}
+	if e.null {
+		return fmt.Errorf("types: cannot scan a NULL array element into %s", t)
+	}
+	if isArraySlice(t) {
+		return fmt.Errorf("types: cannot scan %q into the nested array %s", e.s, t)
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		p := reflect.New(t.Elem())
+		if err := assignElem(p.Elem(), e); err != nil {
+			return err
+		}
+		v.Set(p)
+	case reflect.String:
+		v.SetString(e.s)
+	case reflect.Bool:
+		switch strings.ToLower(e.s) {
+		case "t", "true":
+			v.SetBool(true)
+		case "f", "false":
+			v.SetBool(false)
+		default:
+			return fmt.Errorf("types: invalid bool array element %q", e.s)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(e.s, 10, t.Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(e.s, 10, t.Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		s := e.s
+		switch s {
+		case "Infinity":
+			s = "+Inf"
+		case "-Infinity":
+			s = "-Inf"
+		}
+		f, err := strconv.ParseFloat(s, t.Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		// a bytea element, in the hex format
+		if !strings.HasPrefix(e.s, `\x`) {
+			return fmt.Errorf("types: unsupported bytea format in array element %q", e.s)
+		}
+		b, err := hex.DecodeString(e.s[2:])
+		if err != nil {
+			return err
+		}
+		v.SetBytes(b)
+	case reflect.Struct:
+		if t != _timeType {
+			return fmt.Errorf("types: unsupported array element type %s", t)
+		}
+		tm, err := parseTimestamp(e.s)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(tm))
+	default:
+		return fmt.Errorf("types: unsupported array element type %s", t)
+	}
+	return nil
+}
+
+// timestampFormats are the formats in which postgres outputs timestamp,
+// timestamptz and date values, with the ISO DateStyle.
+var timestampFormats = []string{
+	"2006-01-02 15:04:05.999999999Z07:00:00",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z07",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+	time.RFC3339Nano,
+}
+
+// parseTimestamp parses a timestamp, timestamptz or date value.
+func parseTimestamp(s string) (time.Time, error) {
+	for _, layout := range timestampFormats {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("types: invalid timestamp %q", s)
+}
+
+// appendArray appends the slice v to b as an array literal.
+func appendArray(b *strings.Builder, v reflect.Value) error {
+	b.WriteByte('{')
+	for i := 0; i < v.Len(); i++ {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		if err := appendElem(b, v.Index(i)); err != nil {
+			return err
+		}
+	}
+	b.WriteByte('}')
+	return nil
+}
+
+func appendElem(b *strings.Builder, v reflect.Value) error {
+	t := v.Type()
+	if isArraySlice(t) {
+		if v.IsNil() {
+			return fmt.Errorf("types: nil nested array in %s", t)
+		}
+		return appendArray(b, v)
+	}
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			b.WriteString("NULL")
+			return nil
+		}
+	}
+	if t.Implements(_valuerType) {
+		if t.Kind() == reflect.Ptr && v.IsNil() {
+			b.WriteString("NULL")
+			return nil
+		}
+		dv, err := v.Interface().(driver.Valuer).Value()
+		if err != nil {
+			return err
+		}
+		return appendValue(b, dv)
+	}
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return appendElem(b, v.Elem())
+	case reflect.String:
+		appendQuoted(b, v.String())
+	case reflect.Bool:
+		if v.Bool() {
+			b.WriteByte('t')
+		} else {
+			b.WriteByte('f')
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		b.WriteString(strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		b.WriteString(strconv.FormatUint(v.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		appendFloat(b, v.Float(), t.Bits())
+	case reflect.Slice:
+		if v.IsNil() {
+			b.WriteString("NULL")
+			return nil
+		}
+		appendBytea(b, v.Bytes())
+	case reflect.Struct:
+		if t != _timeType {
+			return fmt.Errorf("types: unsupported array element type %s", t)
+		}
+		appendQuoted(b, v.Interface().(time.Time).Format(time.RFC3339Nano))
+	default:
+		return fmt.Errorf("types: unsupported array element type %s", t)
+	}
+	return nil
+}
+
+// appendValue appends a driver.Value to b as an array element.
+func appendValue(b *strings.Builder, v driver.Value) error {
+	switch v := v.(type) {
+	case nil:
+		b.WriteString("NULL")
+	case string:
+		appendQuoted(b, v)
+	case []byte:
+		if v == nil {
+			b.WriteString("NULL")
+			return nil
+		}
+		appendBytea(b, v)
+	case bool:
+		b.WriteString(strconv.FormatBool(v))
+	case int64:
+		b.WriteString(strconv.FormatInt(v, 10))
+	case float64:
+		appendFloat(b, v, 64)
+	case time.Time:
+		appendQuoted(b, v.Format(time.RFC3339Nano))
+	default:
+		return fmt.Errorf("types: unsupported array element value %T", v)
+	}
+	return nil
+}
+
+func appendFloat(b *strings.Builder, f float64, bits int) {
+	switch {
+	case math.IsInf(f, 1):
+		b.WriteString("Infinity")
+	case math.IsInf(f, -1):
+		b.WriteString("-Infinity")
+	default:
+		b.WriteString(strconv.FormatFloat(f, 'g', -1, bits))
+	}
+}
+
+// appendBytea appends p to b as a bytea array element, in the hex format.
+func appendBytea(b *strings.Builder, p []byte) {
+	appendQuoted(b, `\x`+hex.EncodeToString(p))
+}
+
+// appendQuoted appends s to b as a quoted array element.
+func appendQuoted(b *strings.Builder, s string) {
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c == '"' || c == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(s[i])
+	}
+	b.WriteByte('"')
+}
@@ -0,0 +1,133 @@
+package types
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"testing"
+
+	"github.com/bitbus/sqlx"
+)
+
+func TestArrayScan(t *testing.T) {
+	var s Array[string]
+	if err := s.Scan(`{a,NULL}`); err == nil {
+		t.Error("expected an error scanning NULL into a string")
+	}
+	expected := Array[string]{"a", "b c", `d,"e"`, `f\g`, "", "NULL", "h i"}
+	if err := s.Scan(`{a,"b c","d,\"e\"","f\\g","","NULL", h i }`); err != nil || !reflect.DeepEqual(s, expected) {
+		t.Errorf("expected %q, got %q, %v", expected, s, err)
+	}
+
+	var ps Array[*string]
+	if err := ps.Scan([]byte(`{x,NULL}`)); err != nil {
+		t.Fatal(err)
+	}
+	if len(ps) != 2 || *ps[0] != "x" || ps[1] != nil {
+		t.Errorf("unexpected %v", ps)
+	}
+
+	var ns Array[sql.NullInt64]
+	if err := ns.Scan(`{1,NULL}`); err != nil {
+		t.Fatal(err)
+	}
+	if len(ns) != 2 || ns[0].Int64 != 1 || ns[1].Valid {
+		t.Errorf("unexpected %v", ns)
+	}
+
+	var ints Array[[]int64]
+	if err := ints.Scan(`[0:1][1:2]={{1,2},{3,-4}}`); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ints, Array[[]int64]{{1, 2}, {3, -4}}) {
+		t.Errorf("unexpected %v", ints)
+	}
+
+	var nested Array[Array[bool]]
+	if err := nested.Scan(`{{t,f},{}}`); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(nested, Array[Array[bool]]{{true, false}, {}}) {
+		t.Errorf("unexpected %v", nested)
+	}
+
+	var b Array[[]byte]
+	if err := b.Scan(`{"\\x0102",NULL}`); err != nil {
+		t.Fatal(err)
+	}
+	if len(b) != 2 || !reflect.DeepEqual(b[0], []byte{1, 2}) || b[1] != nil {
+		t.Errorf("unexpected %v", b)
+	}
+
+	if err := s.Scan(nil); err != nil || s != nil {
+		t.Errorf("expected nil, got %v, %v", s, err)
+	}
+
+	var i Array[int]
+	for _, src := range []any{`{1,NULL}`, `{1,2`, `{1,{2}}`, `{"1"x}`, `{1} x`, `{,}`, `{x}`, 42} {
+		if err := i.Scan(src); err == nil {
+			t.Errorf("expected an error scanning %v", src)
+		}
+	}
+}
+
+// blob is a Scanner and Valuer whose Value is a []byte.
+type blob []byte
+
+func (b *blob) Scan(src any) error {
+	*b = append((*b)[:0], src.(string)...)
+	return nil
+}
+
+func (b blob) Value() (driver.Value, error) {
+	return []byte(b), nil
+}
+
+func TestArrayValue(t *testing.T) {
+	one := "one"
+	tests := []struct {
+		v        any
+		expected any
+	}{
+		{Array[string](nil), nil},
+		{Array[string]{}, "{}"},
+		{Array[string]{"a", `b "c"`, `d\e`, "NULL"}, `{"a","b \"c\"","d\\e","NULL"}`},
+		{Array[*string]{&one, nil}, `{"one",NULL}`},
+		{Array[int]{1, -2}, "{1,-2}"},
+		{Array[float64]{1.5, 2}, "{1.5,2}"},
+		{Array[bool]{true, false}, "{t,f}"},
+		{Array[[]byte]{{1, 2}}, `{"\\x0102"}`},
+		{Array[blob]{{1, 2}, nil}, `{"\\x0102",NULL}`},
+		{Array[sql.NullString]{{String: "a", Valid: true}, {}}, `{"a",NULL}`},
+		{Array[[]int]{{1, 2}, {3, 4}}, "{{1,2},{3,4}}"},
+		{Array[Array[int]]{{1}, {2}}, "{{1},{2}}"},
+	}
+	for _, test := range tests {
+		v, err := test.v.(driver.Valuer).Value()
+		if err != nil {
+			t.Errorf("%v: %s", test.v, err)
+			continue
+		}
+		if v != test.expected {
+			t.Errorf("expected %v, got %v", test.expected, v)
+		}
+	}
+
+	// values round trip
+	in := Array[string]{"a", `b "c",`, `{d}`, "", " e "}
+	v, _ := in.Value()
+	var out Array[string]
+	if err := out.Scan(v); err != nil || !reflect.DeepEqual(in, out) {
+		t.Errorf("expected %q, got %q, %v", in, out, err)
+	}
+}
+
+func TestArrayIn(t *testing.T) {
+	q, args, err := sqlx.In("SELECT * FROM t WHERE id = ANY(?) AND kind IN (?)", Array[int]{1, 2, 3}, []string{"a", "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q != "SELECT * FROM t WHERE id = ANY(?) AND kind IN (?, ?)" || len(args) != 3 {
+		t.Errorf("expected the Array not to be expanded, got %q %v", q, args)
+	}
+}
@@ -0,0 +1,220 @@
+package types
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+)
+
+// A Codec compresses the data of a Compressed column.  Its ID is written
+// ahead of the data it compresses, so that the data can be decompressed by
+// the same Codec when it is read back, whichever Codec the column is using by
+// then.
+type Codec interface {
+	ID() byte
+	Compress(data []byte) ([]byte, error)
+	Decompress(data []byte) ([]byte, error)
+}
+
+// gzipMagic starts gzipped data, eg. that of a GzippedText.
+const gzipMagic = 0x1f
+
+var (
+	codecsMu sync.RWMutex
+	codecs   = map[byte]Codec{}
+)
+
+func init() {
+	RegisterCodec(Gzip{})
+	RegisterCodec(Zlib{})
+	RegisterCodec(Flate{})
+}
+
+// RegisterCodec makes c available to decompress Compressed data by its ID,
+// eg. for a zstd or snappy Codec.  If RegisterCodec is called twice with the
+// same ID, or with the ID 0x1f which starts gzipped data, it panics.
+func RegisterCodec(c Codec) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	id := c.ID()
+	if id == gzipMagic {
+		panic("types: RegisterCodec called with the reserved id 0x1f")
+	}
+	if _, dup := codecs[id]; dup {
+		panic(fmt.Sprintf("types: RegisterCodec called twice for id %d", id))
+	}
+	codecs[id] = c
+}
+
+// CodecByID returns the registered Codec with the ID id.
+func CodecByID(id byte) (Codec, bool) {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	c, ok := codecs[id]
+	return c, ok
+}
+
+// compress returns data compressed by w.
+func compress(data []byte, w io.WriteCloser, buf *bytes.Buffer) ([]byte, error) {
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decompress returns the data read from r, closing it.
+func decompress(r io.ReadCloser, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// Gzip is a Codec for the gzip format, with the ID 1.
+type Gzip struct{}
+
+// ID returns the ID of the Codec.
+func (Gzip) ID() byte { return 1 }
+
+// Compress gzips data.
+func (Gzip) Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	return compress(data, gzip.NewWriter(&buf), &buf)
+}
+
+// Decompress ungzips data.
+func (Gzip) Decompress(data []byte) ([]byte, error) {
+	return decompress(gzip.NewReader(bytes.NewReader(data)))
+}
+
+// Zlib is a Codec for the zlib format, with the ID 2.
+type Zlib struct{}
+
+// ID returns the ID of the Codec.
+func (Zlib) ID() byte { return 2 }
+
+// Compress compresses data in the zlib format.
+func (Zlib) Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	return compress(data, zlib.NewWriter(&buf), &buf)
+}
+
+// Decompress decompresses data in the zlib format.
+func (Zlib) Decompress(data []byte) ([]byte, error) {
+	return decompress(zlib.NewReader(bytes.NewReader(data)))
+}
+
+// Flate is a Codec for the raw deflate format, with the ID 3.
+type Flate struct{}
+
+// ID returns the ID of the Codec.
+func (Flate) ID() byte { return 3 }
+
+// Compress deflates data.
+func (Flate) Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, flate.DefaultCompression)
+	if err != nil {
+		return nil, err
+	}
+	return compress(data, w, &buf)
+}
+
+// Decompress inflates data.
+func (Flate) Decompress(data []byte) ([]byte, error) {
+	return decompress(flate.NewReader(bytes.NewReader(data)), nil)
+}
+
+// Compressed[C] is a []byte which is transparently compressed by the Codec C
+// when submitted to a database and decompressed when Scanned from a database,
+// eg. a Compressed[types.Zlib].  The compressed data starts with the ID of
+// its Codec, and Scan decompresses it with the registered Codec of that ID, so
+// that a column can change its Codec without rewriting the data it holds.
+// Scan also reads the headerless data of a GzippedText.
+//
+// C may be a pointer to a Codec type, eg. one which holds a compression level,
+// in which case the Codec registered for its ID is used, as only that one is
+// set up.  A nil Compressed[C] is NULL.
+type Compressed[C Codec] []byte
+
+// codecOf returns the Codec of C:  the registered Codec of its ID if C is a
+// pointer type, and the zero C otherwise.
+func codecOf[C Codec]() (Codec, error) {
+	var codec C
+	t := reflect.TypeOf((*C)(nil)).Elem()
+	switch t.Kind() {
+	case reflect.Ptr:
+		id := reflect.New(t.Elem()).Interface().(Codec).ID()
+		if c, ok := CodecByID(id); ok && reflect.TypeOf(c) == t {
+			return c, nil
+		}
+		return nil, fmt.Errorf("types: no codec %s registered for id %d", t, id)
+	case reflect.Interface:
+		return nil, fmt.Errorf("types: Compressed of the interface type %s", t)
+	}
+	return codec, nil
+}
+
+// Value implements the driver.Valuer interface, compressing the raw value of
+// this Compressed[C] behind the ID of C.
+func (c Compressed[C]) Value() (driver.Value, error) {
+	if c == nil {
+		return nil, nil
+	}
+	codec, err := codecOf[C]()
+	if err != nil {
+		return nil, err
+	}
+	data, err := codec.Compress(c)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{codec.ID()}, data...), nil
+}
+
+// Scan implements the sql.Scanner interface, decompressing the value coming
+// off the wire with the Codec of its ID and storing the raw result in the
+// Compressed[C].
+func (c *Compressed[C]) Scan(src any) error {
+	var source []byte
+	switch t := src.(type) {
+	case string:
+		source = []byte(t)
+	case []byte:
+		source = t
+	case nil:
+		*c = nil
+		return nil
+	default:
+		return errors.New("incompatible type for Compressed")
+	}
+	if len(source) == 0 {
+		return errors.New("types: no codec id in Compressed data")
+	}
+
+	var codec Codec = Gzip{}
+	if source[0] != gzipMagic {
+		var ok bool
+		if codec, ok = CodecByID(source[0]); !ok {
+			return fmt.Errorf("types: no codec registered for id %d", source[0])
+		}
+		source = source[1:]
+	}
+	data, err := codec.Decompress(source)
+	if err != nil {
+		return err
+	}
+	*c = Compressed[C](data)
+	return nil
+}
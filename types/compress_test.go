@@ -0,0 +1,152 @@
+package types
+
+import (
+	"bytes"
+	"compress/flate"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// reverse is a toy Codec which reverses its data.
+type reverse struct{}
+
+func (reverse) ID() byte { return 200 }
+
+func (reverse) Compress(data []byte) ([]byte, error) {
+	out := make([]byte, len(data))
+	for i, b := range data {
+		out[len(data)-1-i] = b
+	}
+	return out, nil
+}
+
+func (r reverse) Decompress(data []byte) ([]byte, error) {
+	return r.Compress(data)
+}
+
+type failing struct{}
+
+func (failing) ID() byte                            { return 201 }
+func (failing) Compress([]byte) ([]byte, error)     { return nil, errors.New("failing") }
+func (failing) Decompress(d []byte) ([]byte, error) { return d, nil }
+
+// leveled is a Codec which compresses with a level it is set up with.
+type leveled struct {
+	level int
+}
+
+func (*leveled) ID() byte { return 202 }
+
+func (l *leveled) Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, l.level)
+	if err != nil {
+		return nil, err
+	}
+	return compress(data, w, &buf)
+}
+
+func (*leveled) Decompress(data []byte) ([]byte, error) {
+	return Flate{}.Decompress(data)
+}
+
+func init() {
+	RegisterCodec(reverse{})
+	RegisterCodec(&leveled{level: flate.NoCompression})
+}
+
+func TestCompressed(t *testing.T) {
+	text := []byte(strings.Repeat("Hello, world. ", 20))
+
+	var values []any
+	for _, c := range []driver.Valuer{
+		Compressed[Gzip](text), Compressed[Zlib](text), Compressed[Flate](text), Compressed[reverse](text),
+	} {
+		v, err := c.Value()
+		if err != nil {
+			t.Fatal(err)
+		}
+		values = append(values, v)
+	}
+	if b := values[3].([]byte); b[0] != 200 || b[1] != ' ' {
+		t.Errorf("expected the reversed data behind its id, got %q", b[:2])
+	}
+
+	legacy, err := GzippedText(text).Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	values = append(values, legacy)
+
+	// whichever codec wrote the data, any Compressed reads it back
+	for i, v := range values {
+		var c Compressed[Zlib]
+		if err = c.Scan(v); err != nil {
+			t.Fatalf("value %d: %s", i, err)
+		}
+		if !bytes.Equal(c, text) {
+			t.Errorf("value %d: expected %q, got %q", i, text, c)
+		}
+	}
+	if b := values[1].([]byte); len(b) >= len(text) {
+		t.Errorf("expected zlib to compress, got %d bytes", len(b))
+	}
+
+	var c Compressed[Gzip]
+	if err = c.Scan(nil); err != nil || c != nil {
+		t.Errorf("expected nil, got %q, %v", c, err)
+	}
+	if v, err := c.Value(); v != nil || err != nil {
+		t.Errorf("expected a nil value, got %v, %v", v, err)
+	}
+	if v, _ := (Compressed[Gzip]{}).Value(); v == nil {
+		t.Error("expected an empty value to be compressed")
+	}
+	for _, src := range []any{[]byte{}, []byte{99, 1}, []byte{2, 1}, 42} {
+		if err = c.Scan(src); err == nil {
+			t.Errorf("expected an error scanning %v", src)
+		}
+	}
+	if _, err = (Compressed[failing]("x")).Value(); err == nil {
+		t.Error("expected the codec error")
+	}
+
+	// a pointer Codec compresses as it was registered, here without compressing
+	v, err := Compressed[*leveled](text).Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := v.([]byte); b[0] != 202 || len(b) <= len(text) {
+		t.Errorf("expected the stored data behind its id, got %d bytes", len(b))
+	}
+	if err = c.Scan(v); err != nil || !bytes.Equal(c, text) {
+		t.Errorf("expected %q, got %q, %v", text, c, err)
+	}
+	if _, err = (Compressed[*Gzip]("x")).Value(); err == nil {
+		t.Error("expected an error for an unregistered pointer codec")
+	}
+	if _, err = (Compressed[Codec]("x")).Value(); err == nil {
+		t.Error("expected an error for an interface codec")
+	}
+}
+
+func TestRegisterCodec(t *testing.T) {
+	for _, c := range []Codec{reverse{}, Gzip{}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected a panic registering %T twice", c)
+				}
+			}()
+			RegisterCodec(c)
+		}()
+	}
+	if c, ok := CodecByID(2); !ok || c != (Zlib{}) {
+		t.Errorf("expected Zlib, got %v", c)
+	}
+	if _, ok := CodecByID(42); ok {
+		t.Error("expected no codec for id 42")
+	}
+}
@@ -0,0 +1,246 @@
+package types
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// A KeyProvider provides the AES keys of Encrypted columns.  Data is sealed
+// with the current key, whose ID is stored with the ciphertext, so that it
+// is opened with the same key after the current key has been rotated.
+type KeyProvider interface {
+	// CurrentKey returns the key to seal data with, and its ID.
+	CurrentKey() (id string, key []byte, err error)
+	// Key returns the key with the ID id.
+	Key(id string) ([]byte, error)
+}
+
+// encryptedVersion is the first byte of the ciphertext of an Encrypted.
+const encryptedVersion = 1
+
+var (
+	keyProviderMu sync.RWMutex
+	keyProvider   KeyProvider
+)
+
+// SetKeyProvider sets the KeyProvider of Encrypted columns.
+func SetKeyProvider(p KeyProvider) {
+	keyProviderMu.Lock()
+	defer keyProviderMu.Unlock()
+	keyProvider = p
+}
+
+func getKeyProvider() (KeyProvider, error) {
+	keyProviderMu.RLock()
+	defer keyProviderMu.RUnlock()
+	if keyProvider == nil {
+		return nil, errors.New("types: no KeyProvider set for Encrypted")
+	}
+	return keyProvider, nil
+}
+
+// LocalKeyProvider is a KeyProvider of keys held in memory, eg. loaded from
+// the environment or a file.  It is safe for concurrent use.
+type LocalKeyProvider struct {
+	mu      sync.RWMutex
+	current string
+	keys    map[string][]byte
+}
+
+// NewLocalKeyProvider returns a LocalKeyProvider of keys whose current key is
+// the key with the ID current.  Each key must be 16, 24 or 32 bytes long, for
+// AES-128, AES-192 or AES-256.
+func NewLocalKeyProvider(current string, keys map[string][]byte) (*LocalKeyProvider, error) {
+	p := &LocalKeyProvider{keys: map[string][]byte{}}
+	for id, key := range keys {
+		if err := p.add(id, key); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := p.keys[current]; !ok {
+		return nil, fmt.Errorf("types: no key with the current id %q", current)
+	}
+	p.current = current
+	return p, nil
+}
+
+func (p *LocalKeyProvider) add(id string, key []byte) error {
+	if len(id) == 0 || len(id) > 255 {
+		return fmt.Errorf("types: invalid key id %q", id)
+	}
+	switch len(key) {
+	case 16, 24, 32:
+	default:
+		return fmt.Errorf("types: invalid key size %d for key id %q", len(key), id)
+	}
+	p.keys[id] = append([]byte(nil), key...)
+	return nil
+}
+
+// Rotate adds key with the ID id, and makes it the current key.  The keys it
+// replaces are kept to open the data they sealed.
+func (p *LocalKeyProvider) Rotate(id string, key []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.add(id, key); err != nil {
+		return err
+	}
+	p.current = id
+	return nil
+}
+
+// CurrentKey returns the current key and its ID.
+func (p *LocalKeyProvider) CurrentKey() (string, []byte, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.current, p.keys[p.current], nil
+}
+
+// Key returns the key with the ID id.
+func (p *LocalKeyProvider) Key(id string) ([]byte, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	key, ok := p.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("types: no key with the id %q", id)
+	}
+	return key, nil
+}
+
+// Encrypted[T] is a T which is transparently sealed with AES-GCM when
+// submitted to a database and opened when Scanned from a database, using the
+// keys of the KeyProvider set by SetKeyProvider.  T may be a string, a []byte
+// or a Serializable[T], as for a Binary[T]:
+//
+//	types.SetKeyProvider(keys)
+//	...
+//	type Person struct {
+//		ID  int                     `db:"id"`
+//		SSN types.Encrypted[string] `db:"ssn"`
+//	}
+//
+// The ciphertext is a version byte, the length and ID of the key, the nonce
+// and the sealed data, which is authenticated together with the key ID.  As
+// each Value uses a random nonce, equal values have different ciphertexts, so
+// Encrypted columns can't be searched or indexed by value.  Scan a NULL into
+// a *Encrypted[T].
+type Encrypted[T any] struct {
+	Data T
+}
+
+// aead returns the AES-GCM cipher of key.
+func aead(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Value implements the driver.Valuer interface, sealing the marshalled Data
+// of this Encrypted[T] with the current key.
+func (e Encrypted[T]) Value() (driver.Value, error) {
+	var plaintext []byte
+	switch d := any(e.Data).(type) {
+	case string:
+		plaintext = []byte(d)
+	case []byte:
+		plaintext = d
+	case Serializable[T]:
+		var err error
+		if plaintext, err = d.MarshalBinary(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("types: unsupported type %T for Encrypted", e.Data)
+	}
+
+	p, err := getKeyProvider()
+	if err != nil {
+		return nil, err
+	}
+	id, key, err := p.CurrentKey()
+	if err != nil {
+		return nil, err
+	}
+	if len(id) == 0 || len(id) > 255 {
+		return nil, fmt.Errorf("types: invalid key id %q", id)
+	}
+	gcm, err := aead(key)
+	if err != nil {
+		return nil, err
+	}
+
+	header := append([]byte{encryptedVersion, byte(len(id))}, id...)
+	out := make([]byte, len(header)+gcm.NonceSize(), len(header)+gcm.NonceSize()+len(plaintext)+gcm.Overhead())
+	copy(out, header)
+	nonce := out[len(header):]
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(out, nonce, plaintext, header), nil
+}
+
+// Scan implements the sql.Scanner interface, opening the ciphertext coming
+// off the wire with the key of its ID and unmarshalling it into the Data of
+// the Encrypted[T].
+func (e *Encrypted[T]) Scan(src any) error {
+	var source []byte
+	switch t := src.(type) {
+	case string:
+		source = []byte(t)
+	case []byte:
+		source = t
+	default:
+		return errors.New("incompatible type for Encrypted")
+	}
+
+	if len(source) < 2 || source[0] != encryptedVersion || len(source) < 2+int(source[1]) {
+		return errors.New("types: invalid Encrypted ciphertext")
+	}
+	header := source[:2+int(source[1])]
+	id := string(header[2:])
+
+	p, err := getKeyProvider()
+	if err != nil {
+		return err
+	}
+	key, err := p.Key(id)
+	if err != nil {
+		return err
+	}
+	gcm, err := aead(key)
+	if err != nil {
+		return err
+	}
+	rest := source[len(header):]
+	if len(rest) < gcm.NonceSize() {
+		return errors.New("types: invalid Encrypted ciphertext")
+	}
+	plaintext, err := gcm.Open(nil, rest[:gcm.NonceSize()], rest[gcm.NonceSize():], header)
+	if err != nil {
+		return err
+	}
+
+	switch d := any(&e.Data).(type) {
+	case *string:
+		*d = string(plaintext)
+	case *[]byte:
+		*d = plaintext
+	default:
+		s, ok := any(e.Data).(Serializable[T])
+		if !ok {
+			return fmt.Errorf("types: unsupported type %T for Encrypted", e.Data)
+		}
+		if e.Data, err = s.UnmarshalBinary(plaintext); err != nil {
+			return err
+		}
+	}
+	return nil
+}
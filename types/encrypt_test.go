@@ -0,0 +1,107 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestEncrypted(t *testing.T) {
+	keys, err := NewLocalKeyProvider("k1", map[string][]byte{"k1": bytes.Repeat([]byte{1}, 32)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetKeyProvider(keys)
+	defer SetKeyProvider(nil)
+
+	e := Encrypted[string]{Data: "123-45-6789"}
+	v, err := e.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed := v.([]byte)
+	if bytes.Contains(sealed, []byte(e.Data)) || !bytes.Equal(sealed[:4], []byte{1, 2, 'k', '1'}) {
+		t.Errorf("unexpected ciphertext %q", sealed)
+	}
+	if v2, _ := e.Value(); bytes.Equal(v2.([]byte), sealed) {
+		t.Error("expected a fresh nonce for each value")
+	}
+
+	// data sealed with a rotated key stays readable
+	if err = keys.Rotate("k2", bytes.Repeat([]byte{2}, 16)); err != nil {
+		t.Fatal(err)
+	}
+	var s Encrypted[string]
+	if err = s.Scan(sealed); err != nil || s.Data != e.Data {
+		t.Errorf("expected %q, got %q, %v", e.Data, s.Data, err)
+	}
+	v, err = s.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(v.([]byte)[2:4]) != "k2" {
+		t.Errorf("expected the current key id, got %q", v)
+	}
+	if err = s.Scan(string(v.([]byte))); err != nil || s.Data != e.Data {
+		t.Errorf("expected %q, got %q, %v", e.Data, s.Data, err)
+	}
+
+	b := Encrypted[[]byte]{Data: []byte{0, 1, 2}}
+	if v, err = b.Value(); err != nil {
+		t.Fatal(err)
+	}
+	b.Data = nil
+	if err = b.Scan(v); err != nil || !bytes.Equal(b.Data, []byte{0, 1, 2}) {
+		t.Errorf("unexpected %v, %v", b.Data, err)
+	}
+
+	j := Encrypted[*fakeJson]{Data: &fakeJson{RawMessage: json.RawMessage(`{"foo":1}`)}}
+	if v, err = j.Value(); err != nil {
+		t.Fatal(err)
+	}
+	var j2 Encrypted[*fakeJson]
+	if err = j2.Scan(v); err != nil || string(j2.Data.RawMessage) != `{"foo":1}` {
+		t.Errorf("unexpected %v, %v", j2.Data, err)
+	}
+
+	tampered := append([]byte(nil), sealed...)
+	tampered[len(tampered)-1] ^= 1
+	unknown := append([]byte(nil), sealed...)
+	unknown[3] = '9'
+	for _, src := range []any{tampered, unknown, sealed[:10], []byte{2, 0}, []byte{1, 5, 'k'}, nil} {
+		if err = s.Scan(src); err == nil {
+			t.Errorf("expected an error scanning %q", src)
+		}
+	}
+	if _, err = (Encrypted[int]{Data: 1}).Value(); err == nil {
+		t.Error("expected an error for an unsupported type")
+	}
+
+	SetKeyProvider(nil)
+	if _, err = e.Value(); err == nil {
+		t.Error("expected an error without a KeyProvider")
+	}
+}
+
+func TestLocalKeyProvider(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 16)
+	if _, err := NewLocalKeyProvider("k2", map[string][]byte{"k1": key}); err == nil {
+		t.Error("expected an error for a missing current key")
+	}
+	if _, err := NewLocalKeyProvider("k1", map[string][]byte{"k1": key[:10]}); err == nil {
+		t.Error("expected an error for an invalid key size")
+	}
+	p, err := NewLocalKeyProvider("k1", map[string][]byte{"k1": key})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = p.Rotate("", key); err == nil {
+		t.Error("expected an error for an empty key id")
+	}
+	if id, _, _ := p.CurrentKey(); id != "k1" {
+		t.Errorf("expected k1 to stay current, got %q", id)
+	}
+	if _, err = p.Key("k2"); err == nil {
+		t.Error("expected an error for an unknown key")
+	}
+}
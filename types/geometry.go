@@ -0,0 +1,119 @@
+package types
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Point is a postgres point.
+type Point struct {
+	X, Y float64
+}
+
+// Value implements the driver.Valuer interface, formatting the Point as
+// `(x,y)`.
+func (p Point) Value() (driver.Value, error) {
+	return p.String(), nil
+}
+
+// String returns the Point as `(x,y)`.
+func (p Point) String() string {
+	return "(" + strconv.FormatFloat(p.X, 'g', -1, 64) + "," + strconv.FormatFloat(p.Y, 'g', -1, 64) + ")"
+}
+
+// Scan implements the sql.Scanner interface, parsing the `(x,y)` coming off
+// the wire into the Point.
+func (p *Point) Scan(src any) error {
+	source, err := geometrySource(src, "Point")
+	if err != nil {
+		return err
+	}
+	v, rest, err := parsePoint(source)
+	if err != nil || strings.TrimSpace(rest) != "" {
+		return fmt.Errorf("types: invalid point %q", source)
+	}
+	*p = v
+	return nil
+}
+
+// Box is a postgres box, given by two of its opposite corners.  Postgres
+// stores a box by its upper right and lower left corners, in that order.
+type Box struct {
+	High, Low Point
+}
+
+// Value implements the driver.Valuer interface, formatting the Box as
+// `(x1,y1),(x2,y2)`.
+func (b Box) Value() (driver.Value, error) {
+	return b.String(), nil
+}
+
+// String returns the Box as `(x1,y1),(x2,y2)`.
+func (b Box) String() string {
+	return b.High.String() + "," + b.Low.String()
+}
+
+// Scan implements the sql.Scanner interface, parsing the `(x1,y1),(x2,y2)`
+// coming off the wire into the Box.
+func (b *Box) Scan(src any) error {
+	source, err := geometrySource(src, "Box")
+	if err != nil {
+		return err
+	}
+	s := strings.TrimSpace(source)
+	// the corners may themselves be parenthesized, eg. ((x1,y1),(x2,y2))
+	if strings.HasPrefix(s, "((") && strings.HasSuffix(s, "))") {
+		s = s[1 : len(s)-1]
+	}
+	high, rest, err := parsePoint(s)
+	if err != nil {
+		return fmt.Errorf("types: invalid box %q", source)
+	}
+	rest = strings.TrimSpace(rest)
+	if !strings.HasPrefix(rest, ",") {
+		return fmt.Errorf("types: invalid box %q", source)
+	}
+	low, rest, err := parsePoint(rest[1:])
+	if err != nil || strings.TrimSpace(rest) != "" {
+		return fmt.Errorf("types: invalid box %q", source)
+	}
+	*b = Box{High: high, Low: low}
+	return nil
+}
+
+func geometrySource(src any, name string) (string, error) {
+	switch t := src.(type) {
+	case string:
+		return t, nil
+	case []byte:
+		return string(t), nil
+	default:
+		return "", errors.New("incompatible type for " + name)
+	}
+}
+
+// parsePoint parses a `(x,y)` point from the start of s, returning the rest
+// of s after it.
+func parsePoint(s string) (Point, string, error) {
+	s = strings.TrimSpace(s)
+	end := strings.IndexByte(s, ')')
+	if !strings.HasPrefix(s, "(") || end < 0 {
+		return Point{}, "", errors.New("invalid point")
+	}
+	x, y, ok := strings.Cut(s[1:end], ",")
+	if !ok {
+		return Point{}, "", errors.New("invalid point")
+	}
+	var p Point
+	var err error
+	if p.X, err = strconv.ParseFloat(strings.TrimSpace(x), 64); err != nil {
+		return Point{}, "", err
+	}
+	if p.Y, err = strconv.ParseFloat(strings.TrimSpace(y), 64); err != nil {
+		return Point{}, "", err
+	}
+	return p, s[end+1:], nil
+}
@@ -0,0 +1,37 @@
+package types
+
+import "testing"
+
+func TestPoint(t *testing.T) {
+	var p Point
+	if err := p.Scan("(1.5,-2)"); err != nil || p != (Point{1.5, -2}) {
+		t.Errorf("unexpected %v, %v", p, err)
+	}
+	if v, _ := p.Value(); v != "(1.5,-2)" {
+		t.Errorf("unexpected value %v", v)
+	}
+	for _, src := range []any{"1,2", "(1,2", "(1)", "(a,2)", "(1,2) x", 42} {
+		if err := p.Scan(src); err == nil {
+			t.Errorf("expected an error scanning %v", src)
+		}
+	}
+}
+
+func TestBox(t *testing.T) {
+	expected := Box{High: Point{3, 4}, Low: Point{1, 2}}
+	for _, src := range []any{"(3,4),(1,2)", []byte("((3, 4), (1, 2))")} {
+		var b Box
+		if err := b.Scan(src); err != nil || b != expected {
+			t.Errorf("scanning %s: unexpected %v, %v", src, b, err)
+		}
+	}
+	if v, _ := expected.Value(); v != "(3,4),(1,2)" {
+		t.Errorf("unexpected value %v", v)
+	}
+	var b Box
+	for _, src := range []any{"(3,4)", "(3,4),", "(3,4);(1,2)", nil} {
+		if err := b.Scan(src); err == nil {
+			t.Errorf("expected an error scanning %v", src)
+		}
+	}
+}
@@ -0,0 +1,274 @@
+package types
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Interval is a postgres interval.  Like postgres, it keeps months and days
+// apart from the rest of its length, as their lengths in time vary:  a month
+// may have from 28 to 31 days, and a day 23 or 25 hours across a daylight
+// saving change.  Use Add to add an Interval to a time.
+//
+// Scan parses intervals in any of the postgres, postgres_verbose, sql_standard
+// and iso_8601 IntervalStyles, and Value formats them in iso_8601, which
+// postgres accepts whatever its IntervalStyle.
+type Interval struct {
+	Months   int32
+	Days     int32
+	Duration time.Duration
+}
+
+// Add returns t plus i, adding its months and days before its duration.
+func (i Interval) Add(t time.Time) time.Time {
+	return t.AddDate(0, int(i.Months), int(i.Days)).Add(i.Duration)
+}
+
+// Value implements the driver.Valuer interface, formatting the Interval in
+// the iso_8601 format, eg. `P1M2DT3.5S`.
+func (i Interval) Value() (driver.Value, error) {
+	return i.String(), nil
+}
+
+// String returns the Interval in the iso_8601 format, eg. `P1M2DT3.5S`.
+func (i Interval) String() string {
+	if i == (Interval{}) {
+		return "PT0S"
+	}
+	var b strings.Builder
+	b.WriteByte('P')
+	if i.Months != 0 {
+		b.WriteString(strconv.Itoa(int(i.Months)) + "M")
+	}
+	if i.Days != 0 {
+		b.WriteString(strconv.Itoa(int(i.Days)) + "D")
+	}
+	if i.Duration != 0 {
+		b.WriteString("T" + strconv.FormatFloat(i.Duration.Seconds(), 'f', -1, 64) + "S")
+	}
+	return b.String()
+}
+
+// Scan implements the sql.Scanner interface, parsing the interval coming off
+// the wire into the Interval.
+func (i *Interval) Scan(src any) error {
+	var source string
+	switch t := src.(type) {
+	case string:
+		source = t
+	case []byte:
+		source = string(t)
+	default:
+		return errors.New("incompatible type for Interval")
+	}
+	v, err := ParseInterval(source)
+	if err != nil {
+		return err
+	}
+	*i = v
+	return nil
+}
+
+// intervalUnits are the units of the postgres IntervalStyles, as months, days
+// or a duration.
+var intervalUnits = map[string]struct {
+	months, days int32
+	duration     time.Duration
+}{
+	"millennium": {months: 12000}, "millennia": {months: 12000}, "millenniums": {months: 12000},
+	"century": {months: 1200}, "centuries": {months: 1200},
+	"decade": {months: 120}, "decades": {months: 120},
+	"year": {months: 12}, "years": {months: 12},
+	"mon": {months: 1}, "mons": {months: 1}, "month": {months: 1}, "months": {months: 1},
+	"week": {days: 7}, "weeks": {days: 7},
+	"day": {days: 1}, "days": {days: 1},
+	"hour": {duration: time.Hour}, "hours": {duration: time.Hour},
+	"min": {duration: time.Minute}, "mins": {duration: time.Minute},
+	"minute": {duration: time.Minute}, "minutes": {duration: time.Minute},
+	"sec": {duration: time.Second}, "secs": {duration: time.Second},
+	"second": {duration: time.Second}, "seconds": {duration: time.Second},
+	"millisecond": {duration: time.Millisecond}, "milliseconds": {duration: time.Millisecond},
+	"msec": {duration: time.Millisecond}, "msecs": {duration: time.Millisecond},
+	"microsecond": {duration: time.Microsecond}, "microseconds": {duration: time.Microsecond},
+	"usec": {duration: time.Microsecond}, "usecs": {duration: time.Microsecond},
+}
+
+// ParseInterval parses an interval in any of the postgres IntervalStyles, eg.
+// `1 year 2 mons -3 days +04:05:06.5`, `@ 1 year 2 mons 3 days ago`,
+// `1-2 3 4:05:06` or `P1Y2M3DT4H5M6.5S`.
+func ParseInterval(s string) (Interval, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "P") {
+		return parseISOInterval(s)
+	}
+
+	var i Interval
+	fields := strings.Fields(strings.ToLower(s))
+	ago := false
+	if len(fields) > 0 && fields[0] == "@" {
+		fields = fields[1:]
+	}
+	if len(fields) > 0 && fields[len(fields)-1] == "ago" {
+		fields, ago = fields[:len(fields)-1], true
+	}
+	if len(fields) == 0 {
+		return i, fmt.Errorf("types: invalid interval %q", s)
+	}
+	// as in postgres, the leading sign of a sql_standard interval applies to
+	// all of its fields if none of the others has a sign of its own, so that
+	// `-1 2:03:04` is -(1 day 2:03:04)
+	if sqlStandardSign(fields) {
+		fields[0] = fields[0][1:]
+		ago = !ago
+	}
+
+	for n := 0; n < len(fields); n++ {
+		f := fields[n]
+		switch {
+		case strings.Contains(f, ":"):
+			d, err := parseIntervalTime(f)
+			if err != nil {
+				return i, fmt.Errorf("types: invalid interval %q", s)
+			}
+			i.Duration += d
+		case strings.Contains(strings.TrimLeft(f, "+-"), "-"):
+			// sql_standard years-months
+			neg := strings.HasPrefix(f, "-")
+			y, m, _ := strings.Cut(strings.TrimLeft(f, "+-"), "-")
+			years, err1 := strconv.ParseInt(y, 10, 32)
+			months, err2 := strconv.ParseInt(m, 10, 32)
+			if err1 != nil || err2 != nil {
+				return i, fmt.Errorf("types: invalid interval %q", s)
+			}
+			if total := int32(years*12 + months); neg {
+				i.Months -= total
+			} else {
+				i.Months += total
+			}
+		default:
+			num, err := strconv.ParseFloat(f, 64)
+			if err != nil {
+				return i, fmt.Errorf("types: invalid interval %q", s)
+			}
+			// a number without a unit is a sql_standard number of days
+			unit := intervalUnits["days"]
+			if n+1 < len(fields) {
+				if u, ok := intervalUnits[fields[n+1]]; ok {
+					unit, n = u, n+1
+				}
+			}
+			if unit.duration != 0 {
+				i.Duration += time.Duration(math.Round(num * float64(unit.duration)))
+			} else if num != math.Trunc(num) {
+				return i, fmt.Errorf("types: fractional months or days in interval %q", s)
+			} else {
+				i.Months += int32(num) * unit.months
+				i.Days += int32(num) * unit.days
+			}
+		}
+	}
+	if ago {
+		i = Interval{Months: -i.Months, Days: -i.Days, Duration: -i.Duration}
+	}
+	return i, nil
+}
+
+// sqlStandardSign returns whether fields are those of a sql_standard interval
+// with a leading minus sign and no other sign.
+func sqlStandardSign(fields []string) bool {
+	if len(fields) < 2 || !strings.HasPrefix(fields[0], "-") {
+		return false
+	}
+	for _, f := range fields[1:] {
+		if _, unit := intervalUnits[f]; unit || strings.HasPrefix(f, "+") || strings.HasPrefix(f, "-") {
+			return false
+		}
+	}
+	return true
+}
+
+// parseIntervalTime parses the time of an interval, eg. `-04:05:06.5`.
+func parseIntervalTime(s string) (time.Duration, error) {
+	neg := strings.HasPrefix(s, "-")
+	parts := strings.Split(strings.TrimLeft(s, "+-"), ":")
+	if len(parts) > 3 {
+		return 0, errors.New("invalid time")
+	}
+	var d time.Duration
+	units := []time.Duration{time.Hour, time.Minute, time.Second}
+	for n, p := range parts {
+		if n < len(parts)-1 || n < 2 {
+			v, err := strconv.ParseInt(p, 10, 64)
+			if err != nil {
+				return 0, err
+			}
+			d += time.Duration(v) * units[n]
+			continue
+		}
+		v, err := strconv.ParseFloat(p, 64)
+		if err != nil {
+			return 0, err
+		}
+		d += time.Duration(math.Round(v * float64(time.Second)))
+	}
+	if neg {
+		d = -d
+	}
+	return d, nil
+}
+
+// parseISOInterval parses an interval in the iso_8601 format with designators,
+// eg. `P1Y2M3DT4H5M6.5S`.
+func parseISOInterval(s string) (Interval, error) {
+	var i Interval
+	rest, inTime := s[1:], false
+	if rest == "" {
+		return i, fmt.Errorf("types: invalid interval %q", s)
+	}
+	for rest != "" {
+		if rest[0] == 'T' {
+			if inTime {
+				return i, fmt.Errorf("types: invalid interval %q", s)
+			}
+			rest, inTime = rest[1:], true
+			continue
+		}
+		n := strings.IndexAny(rest, "YMWDHS")
+		if n <= 0 {
+			return i, fmt.Errorf("types: invalid interval %q", s)
+		}
+		num, err := strconv.ParseFloat(rest[:n], 64)
+		if err != nil {
+			return i, fmt.Errorf("types: invalid interval %q", s)
+		}
+		designator := rest[n]
+		rest = rest[n+1:]
+
+		var unit time.Duration
+		switch {
+		case !inTime && designator == 'Y':
+			i.Months += int32(num * 12)
+		case !inTime && designator == 'M':
+			i.Months += int32(num)
+		case !inTime && designator == 'W':
+			i.Days += int32(num * 7)
+		case !inTime && designator == 'D':
+			i.Days += int32(num)
+		case inTime && designator == 'H':
+			unit = time.Hour
+		case inTime && designator == 'M':
+			unit = time.Minute
+		case inTime && designator == 'S':
+			unit = time.Second
+		default:
+			return i, fmt.Errorf("types: invalid interval %q", s)
+		}
+		i.Duration += time.Duration(math.Round(num * float64(unit)))
+	}
+	return i, nil
+}
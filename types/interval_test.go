@@ -0,0 +1,60 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInterval(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected Interval
+	}{
+		{"00:00:00", Interval{}},
+		{"1 year 2 mons 3 days 04:05:06.5", Interval{14, 3, 4*time.Hour + 5*time.Minute + 6500*time.Millisecond}},
+		{"-1 days +02:03:00", Interval{0, -1, 2*time.Hour + 3*time.Minute}},
+		{"-04:05", Interval{0, 0, -4*time.Hour - 5*time.Minute}},
+		{"@ 1 year 2 mons 3 days 4 hours 5 mins 6.5 secs ago", Interval{-14, -3, -(4*time.Hour + 5*time.Minute + 6500*time.Millisecond)}},
+		{"1-2 3 4:05:06", Interval{14, 3, 4*time.Hour + 5*time.Minute + 6*time.Second}},
+		{"-1-2", Interval{-14, 0, 0}},
+		{"-1 2:03:04", Interval{0, -1, -(2*time.Hour + 3*time.Minute + 4*time.Second)}},
+		{"-1-2 +3 -4:05:06", Interval{-14, 3, -(4*time.Hour + 5*time.Minute + 6*time.Second)}},
+		{"-1-2 3 4:05:06", Interval{-14, -3, -(4*time.Hour + 5*time.Minute + 6*time.Second)}},
+		{"P1Y2M3DT4H5M6.5S", Interval{14, 3, 4*time.Hour + 5*time.Minute + 6500*time.Millisecond}},
+		{"P2W", Interval{0, 14, 0}},
+		{"PT-1.5S", Interval{0, 0, -1500 * time.Millisecond}},
+		{"PT0S", Interval{}},
+	}
+	for _, test := range tests {
+		var i Interval
+		if err := i.Scan(test.src); err != nil {
+			t.Errorf("%q: %s", test.src, err)
+			continue
+		}
+		if i != test.expected {
+			t.Errorf("%q: expected %+v, got %+v", test.src, test.expected, i)
+		}
+		// values round trip
+		v, _ := i.Value()
+		var i2 Interval
+		if err := i2.Scan([]byte(v.(string))); err != nil || i2 != i {
+			t.Errorf("%q: expected %v to round trip, got %+v, %v", test.src, v, i2, err)
+		}
+	}
+
+	if v, _ := (Interval{1, -2, 90 * time.Second}).Value(); v != "P1M-2DT90S" {
+		t.Errorf("unexpected value %v", v)
+	}
+
+	for _, src := range []any{"", "1 fortnight", "1.5 days", "P1H", "PT1D", "1:2:3:4", 42} {
+		var i Interval
+		if err := i.Scan(src); err == nil {
+			t.Errorf("expected an error scanning %v", src)
+		}
+	}
+
+	start := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+	if got := (Interval{Months: 1, Days: 1, Duration: time.Hour}).Add(start); !got.Equal(time.Date(2024, 3, 3, 13, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected time %v", got)
+	}
+}
@@ -0,0 +1,144 @@
+package types
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+// jsonbVersion is the version byte which prefixes jsonb in the binary format
+// of postgres, as some drivers return it.
+const jsonbVersion = 1
+
+var nullJSON = []byte("null")
+
+// jsonSource returns the json in src, which may be a string, a []byte or nil,
+// without the version byte of the jsonb binary format.
+func jsonSource(src any, name string) ([]byte, error) {
+	var source []byte
+	switch t := src.(type) {
+	case string:
+		source = []byte(t)
+	case []byte:
+		source = t
+	case nil:
+	default:
+		return nil, errors.New("incompatible type for " + name)
+	}
+	// json text never starts with a control character, so a leading version
+	// byte can only come from the binary format
+	if len(source) > 0 && source[0] == jsonbVersion {
+		source = source[1:]
+	}
+	return source, nil
+}
+
+// JSON[T] is a T which is transparently marshalled to json when submitted to
+// a database and unmarshalled from json when Scanned from a database, for
+// json, jsonb and text columns:
+//
+//	type Account struct {
+//		ID       int                 `db:"id"`
+//		Settings types.JSON[Settings] `db:"settings"`
+//	}
+//
+// Scan accepts jsonb in the binary format of postgres as well as json text.
+type JSON[T any] struct {
+	Data T
+}
+
+// Value implements the driver.Valuer interface, marshalling the Data of
+// this JSON[T] to a json string.
+func (j JSON[T]) Value() (driver.Value, error) {
+	b, err := json.Marshal(j.Data)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan implements the sql.Scanner interface, unmarshalling the json coming
+// off the wire into the Data of the JSON[T].  A NULL or empty source sets Data
+// to the zero value of T.
+func (j *JSON[T]) Scan(src any) error {
+	source, err := jsonSource(src, "JSON")
+	if err != nil {
+		return err
+	}
+	var data T
+	if len(bytes.TrimSpace(source)) > 0 {
+		if err = json.Unmarshal(source, &data); err != nil {
+			return err
+		}
+	}
+	j.Data = data
+	return nil
+}
+
+// MarshalJSON returns the json encoding of the Data of j.
+func (j JSON[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(j.Data)
+}
+
+// UnmarshalJSON unmarshals data into the Data of j.
+func (j *JSON[T]) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &j.Data)
+}
+
+// NullJSON[T] represents a JSON[T] that may be null.
+// NullJSON[T] implements the scanner interface so
+// it can be used as a scan destination, similar to NullString.
+type NullJSON[T any] struct {
+	Data  T
+	Valid bool // Valid is true if JSON is not NULL
+}
+
+// Value implements the driver.Valuer interface, marshalling the Data of
+// this NullJSON[T] to a json string, or returning nil if it is not Valid.
+func (n NullJSON[T]) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return JSON[T]{Data: n.Data}.Value()
+}
+
+// Scan implements the sql.Scanner interface, unmarshalling the json coming
+// off the wire into the Data of the NullJSON[T].  A NULL source sets Valid to
+// false and Data to the zero value of T.
+func (n *NullJSON[T]) Scan(src any) error {
+	var j JSON[T]
+	if src == nil {
+		n.Data, n.Valid = j.Data, false
+		return nil
+	}
+	if err := j.Scan(src); err != nil {
+		return err
+	}
+	n.Data, n.Valid = j.Data, true
+	return nil
+}
+
+// MarshalJSON returns the json encoding of the Data of n, or null if it is
+// not Valid.
+func (n NullJSON[T]) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return nullJSON, nil
+	}
+	return json.Marshal(n.Data)
+}
+
+// UnmarshalJSON unmarshals data into the Data of n;  null sets Valid to false.
+func (n *NullJSON[T]) UnmarshalJSON(data []byte) error {
+	var zero T
+	if bytes.Equal(bytes.TrimSpace(data), nullJSON) {
+		n.Data, n.Valid = zero, false
+		return nil
+	}
+	n.Data = zero
+	if err := json.Unmarshal(data, &n.Data); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
@@ -0,0 +1,86 @@
+package types
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+type settings struct {
+	Theme string   `json:"theme"`
+	Tags  []string `json:"tags"`
+}
+
+func TestJSON(t *testing.T) {
+	j := JSON[settings]{Data: settings{Theme: "dark", Tags: []string{"a", "b"}}}
+	v, err := j.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != `{"theme":"dark","tags":["a","b"]}` {
+		t.Errorf("unexpected value %v", v)
+	}
+
+	var s JSON[settings]
+	for _, src := range []any{v, []byte(v.(string)), append([]byte{1}, v.(string)...)} {
+		s = JSON[settings]{}
+		if err = s.Scan(src); err != nil {
+			t.Fatalf("scanning %q: %s", src, err)
+		}
+		if !reflect.DeepEqual(s, j) {
+			t.Errorf("expected %v, got %v", j, s)
+		}
+	}
+
+	if err = s.Scan(nil); err != nil || s.Data.Theme != "" || s.Data.Tags != nil {
+		t.Errorf("expected nil to reset the data, got %v, %v", s, err)
+	}
+	if err = s.Scan(42); err == nil {
+		t.Error("expected an error for an incompatible type")
+	}
+	if err = s.Scan(`{"theme": 1}`); err == nil {
+		t.Error("expected an error for a mismatched json type")
+	}
+
+	var m JSON[map[string]int]
+	if err = m.Scan([]byte(`{"a": 1}`)); err != nil || m.Data["a"] != 1 {
+		t.Errorf("unexpected map %v, %v", m.Data, err)
+	}
+
+	b, err := json.Marshal(struct{ S JSON[settings] }{j})
+	if err != nil || string(b) != `{"S":{"theme":"dark","tags":["a","b"]}}` {
+		t.Errorf("unexpected json %s, %v", b, err)
+	}
+}
+
+func TestNullJSON(t *testing.T) {
+	var n NullJSON[[]int]
+	if err := n.Scan(`[1, 2]`); err != nil {
+		t.Fatal(err)
+	}
+	if !n.Valid || len(n.Data) != 2 {
+		t.Errorf("unexpected %v", n)
+	}
+	v, err := n.Value()
+	if err != nil || v != "[1,2]" {
+		t.Errorf("unexpected value %v, %v", v, err)
+	}
+
+	if err = n.Scan(nil); err != nil || n.Valid || n.Data != nil {
+		t.Errorf("expected an invalid NullJSON, got %v, %v", n, err)
+	}
+	if v, err = n.Value(); v != nil || err != nil {
+		t.Errorf("expected a nil value, got %v, %v", v, err)
+	}
+
+	b, err := json.Marshal(n)
+	if err != nil || string(b) != "null" {
+		t.Errorf("expected null, got %s, %v", b, err)
+	}
+	if err = json.Unmarshal([]byte("[3]"), &n); err != nil || !n.Valid || n.Data[0] != 3 {
+		t.Errorf("unexpected %v, %v", n, err)
+	}
+	if err = json.Unmarshal([]byte("null"), &n); err != nil || n.Valid {
+		t.Errorf("expected null to be invalid, got %v, %v", n, err)
+	}
+}
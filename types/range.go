@@ -0,0 +1,161 @@
+package types
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Range[T] is a postgres range, eg. an int4range, numrange, daterange or
+// tstzrange for a Range[int32], Range[float64] or Range[time.Time].  T may be
+// any of the element types of an Array[T] except slices.
+//
+// Each bound is either inclusive, eg. `[` for the lower bound, exclusive, eg.
+// `)` for the upper bound, or infinite, ie. unbounded.  The zero Range[T] is
+// the exclusive range from zero to zero, which postgres stores as empty.  Scan
+// a NULL range into a *Range[T].
+//
+// A lower bound of `-infinity` or an upper bound of `infinity`, as dates and
+// timestamps may have, is scanned as an infinite bound if T can't hold it, eg.
+// for a time.Time.
+type Range[T any] struct {
+	Lower, Upper       T
+	LowerInc, UpperInc bool // the bound is inclusive
+	LowerInf, UpperInf bool // there is no bound
+	Empty              bool
+}
+
+// Value implements the driver.Valuer interface, formatting the Range[T] as a
+// postgres range literal, eg. `[1,10)`.
+func (r Range[T]) Value() (driver.Value, error) {
+	if r.Empty {
+		return "empty", nil
+	}
+	var b strings.Builder
+	if r.LowerInc && !r.LowerInf {
+		b.WriteByte('[')
+	} else {
+		b.WriteByte('(')
+	}
+	if !r.LowerInf {
+		if err := appendBound(&b, reflect.ValueOf(&r.Lower).Elem()); err != nil {
+			return nil, err
+		}
+	}
+	b.WriteByte(',')
+	if !r.UpperInf {
+		if err := appendBound(&b, reflect.ValueOf(&r.Upper).Elem()); err != nil {
+			return nil, err
+		}
+	}
+	if r.UpperInc && !r.UpperInf {
+		b.WriteByte(']')
+	} else {
+		b.WriteByte(')')
+	}
+	return b.String(), nil
+}
+
+// appendBound appends a range bound to b, which is quoted like an array
+// element, except that NULL is not special.
+func appendBound(b *strings.Builder, v reflect.Value) error {
+	if isArraySlice(v.Type()) {
+		return fmt.Errorf("types: unsupported range bound type %s", v.Type())
+	}
+	return appendElem(b, v)
+}
+
+// Scan implements the sql.Scanner interface, parsing the postgres range
+// literal coming off the wire into the Range[T].
+func (r *Range[T]) Scan(src any) error {
+	var source string
+	switch t := src.(type) {
+	case string:
+		source = t
+	case []byte:
+		source = string(t)
+	default:
+		return errors.New("incompatible type for Range")
+	}
+
+	var rng Range[T]
+	s := strings.TrimSpace(source)
+	if strings.EqualFold(s, "empty") {
+		rng.Empty = true
+		*r = rng
+		return nil
+	}
+	if len(s) < 3 || (s[0] != '[' && s[0] != '(') || (s[len(s)-1] != ']' && s[len(s)-1] != ')') {
+		return fmt.Errorf("types: invalid range literal %q", source)
+	}
+	rng.LowerInc, rng.UpperInc = s[0] == '[', s[len(s)-1] == ']'
+
+	lower, rest, err := parseBound(s[1 : len(s)-1])
+	if err != nil || len(rest) == 0 || rest[0] != ',' {
+		return fmt.Errorf("types: invalid range literal %q", source)
+	}
+	upper, rest, err := parseBound(rest[1:])
+	if err != nil || len(rest) != 0 {
+		return fmt.Errorf("types: invalid range literal %q", source)
+	}
+
+	if rng.LowerInf = lower == nil; !rng.LowerInf {
+		if rng.LowerInf, err = scanBound(reflect.ValueOf(&rng.Lower).Elem(), *lower, "-infinity"); err != nil {
+			return err
+		}
+	}
+	if rng.UpperInf = upper == nil; !rng.UpperInf {
+		if rng.UpperInf, err = scanBound(reflect.ValueOf(&rng.Upper).Elem(), *upper, "infinity"); err != nil {
+			return err
+		}
+	}
+	rng.LowerInc = rng.LowerInc && !rng.LowerInf
+	rng.UpperInc = rng.UpperInc && !rng.UpperInf
+	*r = rng
+	return nil
+}
+
+// scanBound assigns the bound s to v, returning whether it is infinite, ie.
+// s is the infinity inf, which v can't hold.
+func scanBound(v reflect.Value, s, inf string) (bool, error) {
+	err := assignElem(v, arrayElem{s: s})
+	if err != nil && strings.EqualFold(s, inf) {
+		v.Set(reflect.Zero(v.Type()))
+		return true, nil
+	}
+	return false, err
+}
+
+// parseBound parses a range bound from the start of s, returning nil for an
+// empty, ie. infinite, bound and the rest of s after it.  A bound may be
+// quoted, with `\` escapes and doubled quotes, or unquoted with `\` escapes.
+func parseBound(s string) (*string, string, error) {
+	var b strings.Builder
+	i, quoted := 0, false
+	for ; i < len(s) && (quoted || s[i] != ','); i++ {
+		switch c := s[i]; {
+		case c == '\\':
+			if i++; i >= len(s) {
+				return nil, "", errors.New("unterminated escape")
+			}
+			b.WriteByte(s[i])
+		case c == '"' && quoted && i+1 < len(s) && s[i+1] == '"':
+			b.WriteByte('"')
+			i++
+		case c == '"':
+			quoted = !quoted
+		default:
+			b.WriteByte(c)
+		}
+	}
+	if quoted {
+		return nil, "", errors.New("unterminated quoted bound")
+	}
+	if i == 0 {
+		return nil, s, nil
+	}
+	bound := b.String()
+	return &bound, s[i:], nil
+}
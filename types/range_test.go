@@ -0,0 +1,91 @@
+package types
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestRange(t *testing.T) {
+	var r Range[int32]
+	if err := r.Scan("[1,10)"); err != nil {
+		t.Fatal(err)
+	}
+	if r != (Range[int32]{Lower: 1, Upper: 10, LowerInc: true}) {
+		t.Errorf("unexpected %+v", r)
+	}
+	if v, err := r.Value(); err != nil || v != "[1,10)" {
+		t.Errorf("unexpected value %v, %v", v, err)
+	}
+
+	if err := r.Scan([]byte("(,5]")); err != nil {
+		t.Fatal(err)
+	}
+	if !r.LowerInf || r.LowerInc || r.UpperInf || !r.UpperInc || r.Upper != 5 {
+		t.Errorf("unexpected %+v", r)
+	}
+	if v, _ := r.Value(); v != "(,5]" {
+		t.Errorf("unexpected value %v", v)
+	}
+
+	if err := r.Scan("empty"); err != nil || !r.Empty {
+		t.Errorf("expected an empty range, got %+v, %v", r, err)
+	}
+	if v, _ := r.Value(); v != "empty" {
+		t.Errorf("unexpected value %v", v)
+	}
+
+	var ts Range[time.Time]
+	if err := ts.Scan(`["2024-01-02 10:00:00+00","2024-01-02 12:30:00.5+05:30")`); err != nil {
+		t.Fatal(err)
+	}
+	lower := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+	upper := time.Date(2024, 1, 2, 7, 0, 0, 5e8, time.UTC)
+	if !ts.Lower.Equal(lower) || !ts.Upper.Equal(upper) || ts.UpperInf {
+		t.Errorf("unexpected %+v", ts)
+	}
+	v, err := ts.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ts2 Range[time.Time]
+	if err = ts2.Scan(v); err != nil || !ts2.Lower.Equal(lower) || !ts2.Upper.Equal(upper) {
+		t.Errorf("expected %v to round trip, got %+v, %v", v, ts2, err)
+	}
+
+	var d Range[time.Time]
+	if err = d.Scan("[2024-01-01,)"); err != nil || !d.UpperInf || d.Lower.Day() != 1 {
+		t.Errorf("unexpected date range %+v, %v", d, err)
+	}
+
+	if err = d.Scan("[-infinity,infinity]"); err != nil || !d.LowerInf || !d.UpperInf || d.LowerInc || d.UpperInc {
+		t.Errorf("unexpected infinite date range %+v, %v", d, err)
+	}
+	if err = d.Scan("[2020-01-01,infinity)"); err != nil || d.LowerInf || d.Lower.Year() != 2020 || !d.UpperInf {
+		t.Errorf("unexpected date range %+v, %v", d, err)
+	}
+	if v, _ = d.Value(); v != `["2020-01-01T00:00:00Z",)` {
+		t.Errorf("unexpected value %v", v)
+	}
+	if err = d.Scan("[2020-01-01,-infinity)"); err == nil {
+		t.Errorf("expected an error for an upper bound of -infinity, got %+v", d)
+	}
+	var f Range[float64]
+	if err = f.Scan("[0,infinity)"); err != nil || f.UpperInf || !math.IsInf(f.Upper, 1) {
+		t.Errorf("unexpected float range %+v, %v", f, err)
+	}
+
+	var s Range[string]
+	if err = s.Scan(`["a,b","c""d\\e"]`); err != nil || s.Lower != "a,b" || s.Upper != `c"d\e` {
+		t.Errorf("unexpected %+v, %v", s, err)
+	}
+	if v, _ = s.Value(); v != `["a,b","c\"d\\e"]` {
+		t.Errorf("unexpected value %v", v)
+	}
+
+	for _, src := range []any{"[1,2", "1,2]", "[1]", "[1,2,3]", `["1,2]`, "[a,2]", nil} {
+		if err = r.Scan(src); err == nil {
+			t.Errorf("expected an error scanning %v", src)
+		}
+	}
+}
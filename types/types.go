@@ -82,19 +82,14 @@ func (b *NullBinary[T]) Scan(src any) (err error) {
 }
 
 // GzippedText is a []byte which transparently gzips data being submitted to
-// a database and ungzips data being Scanned from a database.
+// a database and ungzips data being Scanned from a database.  Its data can be
+// read by a Compressed[C], which also allows other codecs.
 type GzippedText []byte
 
 // Value implements the driver.Valuer interface, gzipping the raw value of
 // this GzippedText.
 func (g GzippedText) Value() (driver.Value, error) {
-	b := make([]byte, 0, len(g))
-	buf := bytes.NewBuffer(b)
-	w := gzip.NewWriter(buf)
-	w.Write(g)
-	w.Close()
-	return buf.Bytes(), nil
-
+	return Gzip{}.Compress(g)
 }
 
 // Scan implements the sql.Scanner interface, ungzipping the value coming off
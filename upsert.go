@@ -0,0 +1,226 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/bitbus/sqlx/reflectx"
+)
+
+// upsert dialects
+const (
+	onConflict = iota + 1
+	onDuplicateKey
+)
+
+// upsertDialect returns which upsert syntax to use for a given drivername.
+// MySQL shares its bindtype with sqlite, so it has to be recognized by name.
+func upsertDialect(driverName string) int {
+	switch driverName {
+	case "mysql", "nrmysql":
+		return onDuplicateKey
+	}
+	switch BindType(driverName) {
+	case DOLLAR, QUESTION:
+		return onConflict
+	}
+	return 0
+}
+
+// upsertColumns returns the columns and the matching named parameters which
+// an upsert of arg writes.  For structs, these are the mapped fields of the
+// struct and of any embedded structs, but not the fields of nested structs.
+// For maps, they are the keys of the map in sorted order.  If arg is an array
+// or slice, its first element is used.
+func upsertColumns(arg any, m *reflectx.Mapper) (columns, params []string, err error) {
+	v := reflect.Indirect(reflect.ValueOf(arg))
+	if k := v.Kind(); k == reflect.Array || k == reflect.Slice {
+		if v.Len() == 0 {
+			return nil, nil, fmt.Errorf("length of array is 0: %#v", arg)
+		}
+		v = reflect.Indirect(v.Index(0))
+		if v.Kind() == reflect.Interface {
+			v = reflect.Indirect(v.Elem())
+		}
+	}
+
+	if v.Kind() == reflect.Map {
+		maparg, ok := convertMapStringInterface(v.Interface())
+		if !ok {
+			return nil, nil, fmt.Errorf("sqlx.upsert: unsupported map type: %T", v.Interface())
+		}
+		for k := range maparg {
+			columns = append(columns, k)
+		}
+		sort.Strings(columns)
+		return columns, columns, nil
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, nil, fmt.Errorf("sqlx.upsert: expected a struct or map, got %s", v.Kind())
+	}
+
+	for _, fi := range m.TypeMap(v.Type()).FlatFields() {
+		columns = append(columns, fi.Name)
+		params = append(params, fi.Path)
+	}
+	if len(columns) == 0 {
+		return nil, nil, fmt.Errorf("sqlx.upsert: no mapped fields in %s", v.Type())
+	}
+	return columns, params, nil
+}
+
+// quoteIdent quotes the identifier name for dialect, with backquotes for MySQL
+// and double quotes otherwise.  A dot separates the parts of a qualified name,
+// eg. schema.table, which are quoted separately.
+func quoteIdent(dialect int, name string) string {
+	quote := `"`
+	if dialect == onDuplicateKey {
+		quote = "`"
+	}
+	parts := strings.Split(name, ".")
+	for i, p := range parts {
+		parts[i] = quote + strings.ReplaceAll(p, quote, quote+quote) + quote
+	}
+	return strings.Join(parts, ".")
+}
+
+// upsertQuery builds a named upsert query for the given drivername, which
+// inserts into table and updates every column which is not one of keys when
+// a row with the same keys already exists.  The table and column names are
+// quoted for the driver.
+func upsertQuery(driverName, table string, columns, params, keys []string) (string, error) {
+	dialect := upsertDialect(driverName)
+	if dialect == 0 {
+		return "", fmt.Errorf("sqlx.upsert: unsupported driver %q", driverName)
+	}
+	if dialect == onConflict && len(keys) == 0 {
+		return "", errors.New("sqlx.upsert: conflict key columns are required")
+	}
+
+	isKey := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		isKey[k] = true
+	}
+	quoted := make([]string, len(columns))
+	for i, c := range columns {
+		quoted[i] = quoteIdent(dialect, c)
+	}
+
+	var b strings.Builder
+	b.WriteString("INSERT INTO ")
+	b.WriteString(quoteIdent(dialect, table))
+	b.WriteString(" (")
+	b.WriteString(strings.Join(quoted, ", "))
+	b.WriteString(") VALUES (")
+	for i, p := range params {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteByte(':')
+		b.WriteString(p)
+	}
+	b.WriteString(")")
+
+	var updates []string
+	for i, c := range columns {
+		if isKey[c] {
+			continue
+		}
+		if q := quoted[i]; dialect == onConflict {
+			updates = append(updates, q+" = EXCLUDED."+q)
+		} else {
+			updates = append(updates, q+" = VALUES("+q+")")
+		}
+	}
+
+	switch dialect {
+	case onConflict:
+		b.WriteString(" ON CONFLICT (")
+		for i, k := range keys {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(quoteIdent(dialect, k))
+		}
+		if len(updates) == 0 {
+			b.WriteString(") DO NOTHING")
+		} else {
+			b.WriteString(") DO UPDATE SET ")
+			b.WriteString(strings.Join(updates, ", "))
+		}
+	case onDuplicateKey:
+		// a no-op update keeps duplicate rows from being an error
+		if len(updates) == 0 {
+			updates = append(updates, quoted[0]+" = "+quoted[0])
+		}
+		b.WriteString(" ON DUPLICATE KEY UPDATE ")
+		b.WriteString(strings.Join(updates, ", "))
+	}
+	return b.String(), nil
+}
+
+func bindUpsert(e binder, table string, arg any, keys []string) (string, error) {
+	columns, params, err := upsertColumns(arg, mapperFor(e))
+	if err != nil {
+		return "", err
+	}
+	return upsertQuery(e.DriverName(), table, columns, params, keys)
+}
+
+// NamedUpsert inserts arg, a struct or map or an array or slice of them, into
+// table.  Rows which conflict with an existing row on the keys columns update
+// that row instead.  The upsert is generated for the Ext's driver, using
+// `ON CONFLICT (keys) DO UPDATE` for postgres and sqlite, and
+// `ON DUPLICATE KEY UPDATE` for MySQL, which ignores keys and uses the
+// table's unique indexes instead.  Slices are inserted with a single
+// multi-row statement, as with NamedExec.
+//
+// The table, the keys and the mapped column names are quoted for the driver,
+// so they may be reserved words, but must not be quoted already.  A dot in
+// table separates a schema from the table name.  Note that quoted names are
+// case sensitive in postgres, so they must be given as they were created.
+func NamedUpsert(e Ext, table string, arg any, keys ...string) (sql.Result, error) {
+	q, err := bindUpsert(e, table, arg, keys)
+	if err != nil {
+		return nil, err
+	}
+	return NamedExec(e, q, arg)
+}
+
+// NamedUpsertContext is like NamedUpsert, but uses the ExtContext's ExecContext.
+func NamedUpsertContext(ctx context.Context, e ExtContext, table string, arg any, keys ...string) (sql.Result, error) {
+	q, err := bindUpsert(e, table, arg, keys)
+	if err != nil {
+		return nil, err
+	}
+	return NamedExecContext(ctx, e, q, arg)
+}
+
+// NamedUpsert using this DB.
+// Any conflicting rows on the keys columns are updated with fields from arg.
+func (db *DB) NamedUpsert(table string, arg any, keys ...string) (sql.Result, error) {
+	return NamedUpsert(db, table, arg, keys...)
+}
+
+// NamedUpsertContext using this DB.
+// Any conflicting rows on the keys columns are updated with fields from arg.
+func (db *DB) NamedUpsertContext(ctx context.Context, table string, arg any, keys ...string) (sql.Result, error) {
+	return NamedUpsertContext(ctx, db, table, arg, keys...)
+}
+
+// NamedUpsert within a transaction.
+// Any conflicting rows on the keys columns are updated with fields from arg.
+func (tx *Tx) NamedUpsert(table string, arg any, keys ...string) (sql.Result, error) {
+	return NamedUpsert(tx, table, arg, keys...)
+}
+
+// NamedUpsertContext within a transaction.
+// Any conflicting rows on the keys columns are updated with fields from arg.
+func (tx *Tx) NamedUpsertContext(ctx context.Context, table string, arg any, keys ...string) (sql.Result, error) {
+	return NamedUpsertContext(ctx, tx, table, arg, keys...)
+}
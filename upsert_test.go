@@ -0,0 +1,123 @@
+package sqlx
+
+import (
+	"strings"
+	"testing"
+)
+
+var upsertSchema = Schema{
+	create: `
+CREATE TABLE kv (
+	k varchar(64) PRIMARY KEY,
+	v text,
+	n integer
+);
+`,
+	drop: `drop table kv;`,
+}
+
+type kvRow struct {
+	K string `db:"k"`
+	V string `db:"v"`
+	N int    `db:"n"`
+}
+
+func TestUpsertQuery(t *testing.T) {
+	columns, params, err := upsertColumns([]kvRow{{}}, mapper())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		driver, want string
+	}{
+		{"postgres", `INSERT INTO "kv" ("k", "v", "n") VALUES (:k, :v, :n) ON CONFLICT ("k") DO UPDATE SET "v" = EXCLUDED."v", "n" = EXCLUDED."n"`},
+		{"sqlite3", `INSERT INTO "kv" ("k", "v", "n") VALUES (:k, :v, :n) ON CONFLICT ("k") DO UPDATE SET "v" = EXCLUDED."v", "n" = EXCLUDED."n"`},
+		{"mysql", "INSERT INTO `kv` (`k`, `v`, `n`) VALUES (:k, :v, :n) ON DUPLICATE KEY UPDATE `v` = VALUES(`v`), `n` = VALUES(`n`)"},
+	}
+	for _, test := range tests {
+		q, err := upsertQuery(test.driver, "kv", columns, params, []string{"k"})
+		if err != nil {
+			t.Error(err)
+		}
+		if q != test.want {
+			t.Errorf("%s:\nexpected: `%s`\ngot:      `%s`", test.driver, test.want, q)
+		}
+	}
+
+	// reserved words and qualified names are quoted
+	q, err := upsertQuery("postgres", "app.order", []string{"key", `a"b`}, []string{"key", "ab"}, []string{"key"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `INSERT INTO "app"."order" ("key", "a""b") VALUES (:key, :ab) ON CONFLICT ("key") DO UPDATE SET "a""b" = EXCLUDED."a""b"`; q != want {
+		t.Errorf("\nexpected: `%s`\ngot:      `%s`", want, q)
+	}
+	if q, err = upsertQuery("mysql", "order", []string{"key"}, []string{"key"}, []string{"key"}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "INSERT INTO `order` (`key`) VALUES (:key) ON DUPLICATE KEY UPDATE `key` = `key`"; q != want {
+		t.Errorf("\nexpected: `%s`\ngot:      `%s`", want, q)
+	}
+
+	if _, err = upsertQuery("sqlserver", "kv", columns, params, []string{"k"}); err == nil {
+		t.Error("expected an error for a driver without upsert support")
+	}
+	if _, err = upsertQuery("postgres", "kv", columns, params, nil); err == nil {
+		t.Error("expected an error without conflict keys")
+	}
+
+	// embedded structs are flattened, nested structs are single columns
+	type base struct {
+		ID int `db:"id"`
+	}
+	type tagged struct {
+		Name string `db:"name"`
+	}
+	type row struct {
+		base
+		tagged `db:"t"`
+		Place  Place `db:"place"`
+		Skip   int   `db:"-"`
+	}
+	columns, params, err = upsertColumns(&row{}, mapper())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "id name place"; strings.Join(columns, " ") != want {
+		t.Errorf("expected columns %q, got %q", want, strings.Join(columns, " "))
+	}
+	if want := "id t.name place"; strings.Join(params, " ") != want {
+		t.Errorf("expected params %q, got %q", want, strings.Join(params, " "))
+	}
+}
+
+func TestNamedUpsert(t *testing.T) {
+	RunWithSchema(upsertSchema, t, func(db *DB, t *testing.T, now string) {
+		_, err := db.NamedUpsert("kv", []kvRow{{"a", "one", 1}, {"b", "two", 2}}, "k")
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = db.NamedUpsert("kv", kvRow{"a", "uno", 10}, "k")
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = db.NamedUpsert("kv", map[string]any{"k": "c", "v": "three", "n": 3}, "k")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var rows []kvRow
+		if err = db.Select(&rows, "SELECT k, v, n FROM kv ORDER BY k"); err != nil {
+			t.Fatal(err)
+		}
+		if len(rows) != 3 {
+			t.Fatalf("expected 3 rows, got %d", len(rows))
+		}
+		if rows[0] != (kvRow{"a", "uno", 10}) {
+			t.Errorf("expected row a to be updated, got %#v", rows[0])
+		}
+		if rows[2] != (kvRow{"c", "three", 3}) {
+			t.Errorf("expected row c to be inserted, got %#v", rows[2])
+		}
+	})
+}